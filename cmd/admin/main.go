@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"mcp-server/internal/auth"
+	"mcp-server/internal/config"
+	"mcp-server/internal/database"
+)
+
+const usage = `usage: admin <command> [flags]
+
+commands:
+  apikeys create -name NAME [-ttl DURATION]   create an API key and print it once
+  apikeys list                                list API keys
+  apikeys revoke -name NAME                   revoke an API key
+  audit [-principal P] [-tool T] [-since DURATION] [-errors] [-limit N]
+                                              list recorded MCP tool calls
+`
+
+type command func(ctx context.Context, repo *database.SQLRepository, args []string) error
+
+var commands = map[string]command{
+	"apikeys create": createAPIKey,
+	"apikeys list":   listAPIKeys,
+	"apikeys revoke": revokeAPIKey,
+	"audit":          listToolCalls,
+}
+
+func main() {
+	name, args := parseCommand(os.Args[1:])
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	// Subcommands parse their own flags, so only the file and environment apply.
+	cfg, err := config.Load("admin", nil)
+	if err != nil {
+		log.Fatalf("invalid configuration:\n%v", err)
+	}
+	db, err := database.Connect(cfg.Database.DSN())
+	if err != nil {
+		log.Fatalf("connect: %v", err)
+	}
+	defer db.Close()
+	if err := cmd(context.Background(), database.NewRepository(db), args); err != nil {
+		log.Fatalf("%s: %v", name, err)
+	}
+}
+
+// parseCommand splits args into a command name of one or two words and its flags.
+func parseCommand(args []string) (string, []string) {
+	if len(args) == 0 {
+		return "", nil
+	}
+	if len(args) > 1 {
+		if _, ok := commands[args[0]+" "+args[1]]; ok {
+			return args[0] + " " + args[1], args[2:]
+		}
+	}
+	return args[0], args[1:]
+}
+
+func createAPIKey(ctx context.Context, repo *database.SQLRepository, args []string) error {
+	fs := flag.NewFlagSet("apikeys create", flag.ExitOnError)
+	name := fs.String("name", "", "unique key name, e.g. the CI pipeline it belongs to")
+	ttl := fs.Duration("ttl", 90*24*time.Hour, "key lifetime")
+	fs.Parse(args)
+	if *name == "" {
+		return fmt.Errorf("-name is required")
+	}
+	if *ttl <= 0 {
+		return fmt.Errorf("-ttl must be positive")
+	}
+	key, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	if err := repo.InsertAPIKey(ctx, database.APIKey{
+		Name:      *name,
+		KeyHash:   hash,
+		CreatedAt: now,
+		ExpiresAt: now.Add(*ttl),
+	}); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "created API key %q, expires %s; it will not be shown again\n", *name, now.Add(*ttl).Format(time.RFC3339))
+	fmt.Println(key)
+	return nil
+}
+
+func listAPIKeys(ctx context.Context, repo *database.SQLRepository, args []string) error {
+	keys, err := repo.ListAPIKeys(ctx)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCREATED\tEXPIRES\tLAST USED\tREVOKED")
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", k.Name, formatTime(k.CreatedAt), formatTime(k.ExpiresAt), formatTime(k.LastUsedAt), formatTime(k.RevokedAt))
+	}
+	return w.Flush()
+}
+
+func revokeAPIKey(ctx context.Context, repo *database.SQLRepository, args []string) error {
+	fs := flag.NewFlagSet("apikeys revoke", flag.ExitOnError)
+	name := fs.String("name", "", "name of the key to revoke")
+	fs.Parse(args)
+	if *name == "" {
+		return fmt.Errorf("-name is required")
+	}
+	if err := repo.RevokeAPIKey(ctx, *name, time.Now().UTC()); err != nil {
+		return err
+	}
+	log.Printf("revoked API key %q", *name)
+	return nil
+}
+
+func listToolCalls(ctx context.Context, repo *database.SQLRepository, args []string) error {
+	fs := flag.NewFlagSet("audit", flag.ExitOnError)
+	principal := fs.String("principal", "", "only calls by this principal, e.g. github:octocat or apikey:ci")
+	tool := fs.String("tool", "", "only calls of this tool")
+	since := fs.Duration("since", 24*time.Hour, "only calls within this long ago")
+	errorsOnly := fs.Bool("errors", false, "only failed calls")
+	limit := fs.Int("limit", database.DefaultToolCallLimit, "maximum number of calls")
+	fs.Parse(args)
+	calls, err := repo.ListToolCalls(ctx, database.ToolCallFilter{
+		Principal:  *principal,
+		Tool:       *tool,
+		Since:      time.Now().Add(-*since),
+		ErrorsOnly: *errorsOnly,
+		Limit:      *limit,
+	})
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CALLED AT\tPRINCIPAL\tTOOL\tLATENCY\tBYTES\tERROR\tARGUMENTS")
+	for _, c := range calls {
+		errText := "-"
+		if c.ErrorCode != "" {
+			errText = c.ErrorCode + " " + c.ErrorMessage
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", formatTime(c.CalledAt), c.Principal, c.Tool, c.Latency, c.ResultBytes, errText, c.Arguments)
+	}
+	return w.Flush()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
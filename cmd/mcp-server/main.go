@@ -1 +1,189 @@
 package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"mcp-server/internal/auth"
+	"mcp-server/internal/buildinfo"
+	"mcp-server/internal/cache"
+	"mcp-server/internal/config"
+	"mcp-server/internal/database"
+	"mcp-server/internal/logging"
+	"mcp-server/internal/mcp"
+	"mcp-server/internal/metrics"
+	"mcp-server/internal/ratelimit"
+	"mcp-server/internal/server"
+	"mcp-server/internal/tracing"
+)
+
+// flushTimeout bounds flushing the audit log and traces after draining.
+// Cloud Run stops an instance 10 seconds after SIGTERM, so the drain
+// timeout plus this must stay below that.
+const flushTimeout = 2 * time.Second
+
+// replicaCheckInterval is how often the server asks the primary whether a
+// sync run finished, to sync an embedded replica right after one.
+const replicaCheckInterval = 30 * time.Second
+
+func main() {
+	cfg := config.MustLoad("mcp-server", os.Args[1:])
+	slog.SetDefault(logging.New(os.Stderr, cfg.Telemetry.LogLevel))
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Endpoint:       cfg.Telemetry.OTLPEndpoint,
+		ServiceName:    mcp.Name,
+		ServiceVersion: buildinfo.Version(),
+	})
+	if err != nil {
+		fatal("tracing", err)
+	}
+
+	// The catalog is only ever read by the server. It gets its own read-only
+	// handle; the read-write one is limited to API keys, quotas, saved
+	// estimates and the audit log.
+	db, err := database.Connect(cfg.Database.DSN())
+	if err != nil {
+		fatal("connect", err)
+	}
+	catalogDB, replica, err := connectCatalog(cfg.Database)
+	if err != nil {
+		fatal("connect catalog", err)
+	}
+	reg := metrics.NewRegistry()
+	serverMetrics := metrics.NewServer(reg)
+	repo := database.NewRepository(db)
+	repo.SetQueryObserver(serverMetrics)
+	catalog := database.NewRepository(catalogDB)
+	catalog.SetQueryObserver(serverMetrics)
+
+	allowlist, err := auth.ParseAllowlist(strings.Join(cfg.GitHub.AllowedOrgs, ","), strings.Join(cfg.GitHub.AllowedTeams, ","))
+	if err != nil {
+		fatal("allowlist", err)
+	}
+	var roles auth.RoleBindings
+	if path := cfg.GitHub.RoleBindingsFile; path != "" {
+		if roles, err = auth.LoadRoleBindings(path); err != nil {
+			fatal("role bindings", err)
+		}
+	}
+
+	authn := auth.NewAuthenticator(auth.NewGitHubClient(cfg.GitHub.APIURL, nil), auth.Options{
+		Allowlist:       allowlist,
+		Roles:           roles,
+		APIKeys:         repo,
+		RecheckInterval: cfg.GitHub.RecheckInterval,
+		Metrics:         serverMetrics,
+	})
+	limits := ratelimit.NewEnforcer(ratelimit.Policy{
+		Principal:  ratelimit.Rate{PerSecond: cfg.Limits.PrincipalRate, Burst: cfg.Limits.PrincipalBurst},
+		Tool:       ratelimit.Rate{PerSecond: cfg.Limits.ToolRate, Burst: cfg.Limits.ToolBurst},
+		DailyQuota: cfg.Limits.DailyQuota,
+	}, repo)
+	var skus mcp.SKUStore = catalog
+	if cfg.Server.CacheSize > 0 {
+		skus = cache.New(catalog, cache.Options{Size: cfg.Server.CacheSize, TTL: cfg.Server.CacheTTL, Metrics: serverMetrics})
+	}
+	audit := mcp.NewAuditLog(repo, cfg.Server.AuditRedactFields, 1024)
+	inFlight := &mcp.InFlight{}
+	mcpServer := mcp.NewServer(mcp.Options{SKUs: skus, Catalog: catalog, MachineTypes: catalog, Estimates: repo, ToolCalls: repo, Audit: audit, Limits: limits, Metrics: serverMetrics, InFlight: inFlight})
+	srv := server.New(server.Options{
+		Authenticator: authn,
+		Limits:        limits,
+		MCP:           mcpServer,
+		Status:        catalog,
+		Version:       buildinfo.Version(),
+		Metrics:       metrics.Handler(reg),
+		MaxSyncAge:    cfg.Server.MaxSyncAge,
+	})
+
+	httpServer := &http.Server{Addr: ":" + strconv.Itoa(cfg.Server.Port), Handler: srv, ReadHeaderTimeout: 10 * time.Second}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	errc := make(chan error, 1)
+	go func() { errc <- httpServer.ListenAndServe() }()
+	if replica != nil {
+		go replica.FollowUpdates(ctx, repo, replicaCheckInterval)
+	}
+	slog.Info("listening", "addr", httpServer.Addr, "version", buildinfo.Version())
+	select {
+	case err := <-errc:
+		fatal("serve", err)
+	case <-ctx.Done():
+		stop()
+	}
+
+	// Refuse new sessions and fail readiness, let running tool calls finish,
+	// then end the remaining sessions so their streams and connections close.
+	slog.Info("shutting down", "timeout", cfg.Server.ShutdownTimeout.String())
+	drainCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	srv.Drain()
+	if err := inFlight.Wait(drainCtx); err != nil {
+		slog.Warn("tool calls still running at shutdown", "error", err)
+	}
+	for ss := range mcpServer.Sessions() {
+		ss.Close()
+	}
+	if err := httpServer.Shutdown(drainCtx); err != nil {
+		slog.Warn("close connections", "error", err)
+		httpServer.Close()
+	}
+
+	flushCtx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+	if err := audit.Close(flushCtx); err != nil {
+		slog.Error("flush audit log", "error", err)
+	}
+	if err := shutdownTracing(flushCtx); err != nil {
+		slog.Error("flush traces", "error", err)
+	}
+	if err := errors.Join(db.Close(), catalogDB.Close()); err != nil {
+		slog.Error("close database", "error", err)
+	}
+	slog.Info("shutdown complete")
+}
+
+// connectCatalog opens a read-only handle on the pricing catalog, through an
+// embedded replica when one is configured so tool reads are served locally.
+// The replica syncs in the background; main also syncs it as soon as a sync
+// run finishes on the primary.
+func connectCatalog(cfg config.Database) (*sql.DB, *database.Replica, error) {
+	if cfg.ReplicaPath == "" {
+		if cfg.ReadOnlyAuthToken == "" && !strings.HasPrefix(cfg.URL, "file:") {
+			slog.Warn("no read-only database token set, reading the catalog with the read-write token")
+		}
+		db, err := database.ConnectReadOnly(cfg.ReadOnlyDSN())
+		return db, nil, err
+	}
+	// A replica forwards writes to the primary, so only a read-only token
+	// keeps a tool from changing the catalog there.
+	if cfg.ReadOnlyAuthToken == "" {
+		return nil, nil, errors.New("an embedded replica needs a read-only token; set TURSO_READ_ONLY_AUTH_TOKEN, -database-read-only-auth-token or database.read_only_auth_token")
+	}
+	replica, err := database.ConnectReplica(context.Background(), database.ReplicaOptions{
+		Path:         cfg.ReplicaPath,
+		PrimaryURL:   cfg.URL,
+		AuthToken:    cfg.ReadOnlyAuthToken,
+		SyncInterval: cfg.ReplicaSyncInterval,
+		ReadOnly:     true,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return replica.DB, replica, nil
+}
+
+// fatal logs err and exits with a non-zero status.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
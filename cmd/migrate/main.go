@@ -1,24 +1,42 @@
 package main
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"os"
 
+	"mcp-server/internal/compute"
+	"mcp-server/internal/config"
 	"mcp-server/internal/database"
+	"mcp-server/internal/logging"
+	"mcp-server/internal/regions"
 )
 
 func main() {
-	url := os.Getenv("DATABASE_URL")
-	if url == "" {
-		url = "file:cloud-pricing.db"
-	}
-	db, err := database.Connect(url)
+	cfg := config.MustLoad("migrate", os.Args[1:])
+	slog.SetDefault(logging.New(os.Stderr, cfg.Telemetry.LogLevel))
+	db, err := database.Connect(cfg.Database.DSN())
 	if err != nil {
-		log.Fatalf("connect: %v", err)
+		fatal("connect", err)
 	}
 	defer db.Close()
 	if err := database.Migrate(db); err != nil {
-		log.Fatalf("migrate: %v", err)
+		fatal("migrate", err)
+	}
+	// Seed regions and machine types so they can be listed and validated
+	// before the first sync.
+	repo := database.NewRepository(db)
+	if _, err := regions.Refresh(context.Background(), repo); err != nil {
+		fatal("seed regions", err)
+	}
+	if err := compute.RefreshMachineTypes(context.Background(), repo); err != nil {
+		fatal("seed machine types", err)
 	}
-	log.Println("migration complete")
+	slog.Info("migration complete")
+}
+
+// fatal logs err and exits with a non-zero status.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -1 +1,107 @@
 package main
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"os"
+	"time"
+
+	"mcp-server/internal/buildinfo"
+	"mcp-server/internal/config"
+	"mcp-server/internal/database"
+	"mcp-server/internal/gcp"
+	"mcp-server/internal/logging"
+	"mcp-server/internal/mcp"
+	"mcp-server/internal/metrics"
+	"mcp-server/internal/sync"
+	"mcp-server/internal/tracing"
+)
+
+// pushTimeout bounds the final metrics push so a slow Pushgateway cannot
+// keep the job alive.
+const pushTimeout = 10 * time.Second
+
+func main() {
+	cfg := config.MustLoad("sync-job", os.Args[1:])
+	slog.SetDefault(logging.New(os.Stderr, cfg.Telemetry.LogLevel))
+	ctx := context.Background()
+	var (
+		db      *sql.DB
+		replica *database.Replica
+		err     error
+	)
+	if cfg.Database.ReplicaPath != "" {
+		replica, err = database.ConnectReplica(ctx, database.ReplicaOptions{
+			Path:         cfg.Database.ReplicaPath,
+			PrimaryURL:   cfg.Database.URL,
+			AuthToken:    cfg.Database.AuthToken,
+			SyncInterval: cfg.Database.ReplicaSyncInterval,
+		})
+		if replica != nil {
+			db = replica.DB
+		}
+	} else {
+		db, err = database.Connect(cfg.Database.DSN())
+	}
+	if err != nil {
+		fatal("connect", err)
+	}
+	defer db.Close()
+
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		Endpoint:       cfg.Telemetry.OTLPEndpoint,
+		ServiceName:    "cloud-pricing-sync",
+		ServiceVersion: buildinfo.Version(),
+	})
+	if err != nil {
+		fatal("tracing", err)
+	}
+	client, err := gcp.NewClient(ctx, cfg.Sync.Currency)
+	if err != nil {
+		fatal("catalog client", err)
+	}
+	defer client.Close()
+
+	reg := metrics.NewRegistry()
+	repo := database.NewRepository(db)
+	job := sync.NewJob(client, repo)
+	job.SetMetrics(metrics.NewSync(reg))
+	job.SetRepricer(mcp.NewEstimateRepricer(repo, repo, repo))
+	job.SetConcurrency(cfg.Sync.Concurrency)
+	runErr := job.Run(ctx)
+	if replica != nil && runErr == nil {
+		// Writes went to the primary; pull them so the local file is current.
+		if err := replica.Sync(ctx); err != nil {
+			slog.Error("sync replica", "error", err)
+		}
+	}
+
+	// The job exits before Prometheus could scrape it, so its metrics are
+	// pushed to a Pushgateway and/or written for the node exporter.
+	if gateway := cfg.Telemetry.PushgatewayURL; gateway != "" {
+		pushCtx, cancel := context.WithTimeout(ctx, pushTimeout)
+		if err := metrics.Push(pushCtx, gateway, "sync-job", reg); err != nil {
+			slog.Error("push metrics", "error", err)
+		}
+		cancel()
+	}
+	if path := cfg.Telemetry.MetricsFile; path != "" {
+		if err := metrics.WriteTextfile(path, reg); err != nil {
+			slog.Error("write metrics", "error", err)
+		}
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Error("flush traces", "error", err)
+	}
+	if runErr != nil {
+		db.Close()
+		os.Exit(1)
+	}
+}
+
+// fatal logs err and exits with a non-zero status.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Allowlist restricts access to members of GitHub organizations or teams.
+// A user is allowed when they belong to any listed organization or team.
+// An empty Allowlist admits every authenticated GitHub user.
+type Allowlist struct {
+	Orgs  []string
+	Teams []Team
+}
+
+// Team identifies a GitHub team by organization and team slug.
+type Team struct {
+	Org  string
+	Slug string
+}
+
+// String returns the team in "org/slug" form.
+func (t Team) String() string {
+	return t.Org + "/" + t.Slug
+}
+
+// ParseAllowlist builds an Allowlist from comma-separated organization names
+// and comma-separated "org/team-slug" team references.
+func ParseAllowlist(orgs, teams string) (Allowlist, error) {
+	a := Allowlist{Orgs: splitList(orgs)}
+	for _, ref := range splitList(teams) {
+		t, err := parseTeam(ref)
+		if err != nil {
+			return Allowlist{}, err
+		}
+		a.Teams = append(a.Teams, t)
+	}
+	return a, nil
+}
+
+func parseTeam(ref string) (Team, error) {
+	org, slug, ok := strings.Cut(ref, "/")
+	if !ok || org == "" || slug == "" || strings.Contains(slug, "/") {
+		return Team{}, fmt.Errorf("invalid team %q: want org/team-slug", ref)
+	}
+	return Team{Org: org, Slug: slug}, nil
+}
+
+// Empty reports whether the allowlist has no entries.
+func (a Allowlist) Empty() bool {
+	return len(a.Orgs) == 0 && len(a.Teams) == 0
+}
+
+// MembershipChecker looks up GitHub organization and team membership for a token owner.
+type MembershipChecker interface {
+	OrgMember(ctx context.Context, token, org string) (bool, error)
+	TeamMember(ctx context.Context, token, org, team, login string) (bool, error)
+}
+
+// Allows reports whether the user identified by token and login belongs to
+// any organization or team on the allowlist.
+func (a Allowlist) Allows(ctx context.Context, gh MembershipChecker, token, login string) (bool, error) {
+	if a.Empty() {
+		return true, nil
+	}
+	for _, org := range a.Orgs {
+		ok, err := gh.OrgMember(ctx, token, org)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	for _, t := range a.Teams {
+		ok, err := gh.TeamMember(ctx, token, t.Org, t.Slug, login)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
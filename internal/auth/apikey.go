@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"mcp-server/internal/database"
+)
+
+// APIKeyPrefix marks bearer tokens that are API keys rather than GitHub tokens.
+const APIKeyPrefix = "cpm_"
+
+// lastUsedResolution limits how often the last-used timestamp of a key is written.
+const lastUsedResolution = time.Minute
+
+// ErrInvalidAPIKey is returned for unknown, revoked or expired API keys.
+var ErrInvalidAPIKey = errors.New("invalid, revoked or expired API key")
+
+// APIKeyStore persists hashed API keys.
+type APIKeyStore interface {
+	APIKeyByHash(ctx context.Context, hash string) (database.APIKey, error)
+	TouchAPIKey(ctx context.Context, keyID int64, at time.Time) error
+}
+
+// GenerateAPIKey returns a new random API key and its hash.
+// The key is shown to the operator once; only the hash is stored.
+func GenerateAPIKey() (key, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	key = APIKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return key, HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hex-encoded SHA-256 hash of key.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAPIKey reports whether token has the API key format.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// authenticateAPIKey resolves the principal for an API key, rejecting
+// unknown, revoked and expired keys with ErrInvalidAPIKey.
+func (a *Authenticator) authenticateAPIKey(ctx context.Context, token string) (Principal, error) {
+	if a.keys == nil {
+		return Principal{}, ErrInvalidAPIKey
+	}
+	k, err := a.keys.APIKeyByHash(ctx, HashAPIKey(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Principal{}, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return Principal{}, fmt.Errorf("look up api key: %w", err)
+	}
+	now := a.now()
+	if !k.RevokedAt.IsZero() || !now.Before(k.ExpiresAt) {
+		return Principal{}, ErrInvalidAPIKey
+	}
+	if now.Sub(k.LastUsedAt) >= lastUsedResolution {
+		if err := a.keys.TouchAPIKey(ctx, k.KeyID, now.UTC()); err != nil {
+			return Principal{}, fmt.Errorf("record api key use: %w", err)
+		}
+	}
+	return Principal{APIKey: k.Name, Role: a.roles.apiKeyRole(k.Name)}, nil
+}
+
+var _ APIKeyStore = (*database.SQLRepository)(nil)
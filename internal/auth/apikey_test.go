@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"mcp-server/internal/database"
+)
+
+type fakeKeyStore struct {
+	keys    map[string]database.APIKey
+	touched map[int64]time.Time
+}
+
+func (f *fakeKeyStore) APIKeyByHash(ctx context.Context, hash string) (database.APIKey, error) {
+	k, ok := f.keys[hash]
+	if !ok {
+		return database.APIKey{}, sql.ErrNoRows
+	}
+	return k, nil
+}
+
+func (f *fakeKeyStore) TouchAPIKey(ctx context.Context, keyID int64, at time.Time) error {
+	f.touched[keyID] = at
+	return nil
+}
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, hash, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if !IsAPIKey(key) || !strings.HasPrefix(key, APIKeyPrefix) {
+		t.Fatalf("key %q lacks prefix", key)
+	}
+	if hash != HashAPIKey(key) || len(hash) != 64 {
+		t.Fatalf("hash %q does not match key", hash)
+	}
+	other, _, err := GenerateAPIKey()
+	if err != nil || other == key {
+		t.Fatalf("second key = %q, %v", other, err)
+	}
+}
+
+func TestAuthenticator_APIKey(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := &fakeKeyStore{
+		keys: map[string]database.APIKey{
+			HashAPIKey("cpm_valid"):   {KeyID: 1, Name: "ci", ExpiresAt: now.Add(time.Hour)},
+			HashAPIKey("cpm_expired"): {KeyID: 2, Name: "old", ExpiresAt: now.Add(-time.Hour)},
+			HashAPIKey("cpm_revoked"): {KeyID: 3, Name: "gone", ExpiresAt: now.Add(time.Hour), RevokedAt: now.Add(-time.Minute)},
+		},
+		touched: map[int64]time.Time{},
+	}
+	a := NewAuthenticator(&fakeGitHub{}, Options{Allowlist: Allowlist{Orgs: []string{"acme"}}, APIKeys: store})
+	a.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	p, err := a.Authenticate(ctx, "cpm_valid")
+	if err != nil {
+		t.Fatalf("valid key: %v", err)
+	}
+	if p.APIKey != "ci" || p.String() != "apikey:ci" {
+		t.Fatalf("principal = %+v", p)
+	}
+	if !store.touched[1].Equal(now) {
+		t.Fatalf("last used not recorded: %v", store.touched)
+	}
+	for _, token := range []string{"cpm_expired", "cpm_revoked", "cpm_unknown"} {
+		if _, err := a.Authenticate(ctx, token); !errors.Is(err, ErrInvalidAPIKey) {
+			t.Errorf("%s: got %v, want ErrInvalidAPIKey", token, err)
+		}
+	}
+}
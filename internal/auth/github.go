@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// DefaultGitHubAPIURL is the base URL of the public GitHub REST API.
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// ErrInvalidToken is returned when GitHub rejects an access token.
+var ErrInvalidToken = errors.New("invalid or expired GitHub token")
+
+// GitHubUser is the subset of the GitHub user object used for authorization.
+type GitHubUser struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+}
+
+// GitHubClient queries the GitHub REST API on behalf of an authenticated user.
+// Organization and team lookups require the token to carry the read:org scope.
+type GitHubClient struct {
+	baseURL string
+	http    *http.Client
+}
+
+// NewGitHubClient creates a GitHubClient for the given API base URL.
+// An empty baseURL selects DefaultGitHubAPIURL and a nil httpClient selects http.DefaultClient.
+func NewGitHubClient(baseURL string, httpClient *http.Client) *GitHubClient {
+	if baseURL == "" {
+		baseURL = DefaultGitHubAPIURL
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &GitHubClient{baseURL: baseURL, http: httpClient}
+}
+
+// User returns the user that owns the token.
+func (c *GitHubClient) User(ctx context.Context, token string) (GitHubUser, error) {
+	var u GitHubUser
+	found, err := c.get(ctx, token, "/user", &u)
+	if err != nil {
+		return GitHubUser{}, err
+	}
+	if !found {
+		return GitHubUser{}, ErrInvalidToken
+	}
+	return u, nil
+}
+
+// OrgMember reports whether the token owner is an active member of org.
+func (c *GitHubClient) OrgMember(ctx context.Context, token, org string) (bool, error) {
+	var m membership
+	found, err := c.get(ctx, token, "/user/memberships/orgs/"+url.PathEscape(org), &m)
+	if err != nil || !found {
+		return false, err
+	}
+	return m.State == "active", nil
+}
+
+// TeamMember reports whether login is an active member of the team identified by org and team slug.
+func (c *GitHubClient) TeamMember(ctx context.Context, token, org, team, login string) (bool, error) {
+	var m membership
+	path := fmt.Sprintf("/orgs/%s/teams/%s/memberships/%s", url.PathEscape(org), url.PathEscape(team), url.PathEscape(login))
+	found, err := c.get(ctx, token, path, &m)
+	if err != nil || !found {
+		return false, err
+	}
+	return m.State == "active", nil
+}
+
+type membership struct {
+	State string `json:"state"`
+}
+
+// get decodes the JSON response for path into out. It reports false when the
+// resource is not visible to the token (404 or 403).
+func (c *GitHubClient) get(ctx context.Context, token, path string, out any) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("github %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return false, ErrInvalidToken
+	case resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0":
+		return false, fmt.Errorf("github %s: rate limit exceeded", path)
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusForbidden:
+		return false, nil
+	case resp.StatusCode != http.StatusOK:
+		return false, fmt.Errorf("github %s: unexpected status %s", path, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("decode github %s: %w", path, err)
+	}
+	return true, nil
+}
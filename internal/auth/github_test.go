@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newFakeGitHubAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /user", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id": 1, "login": "octocat"}`))
+	})
+	mux.HandleFunc("GET /user/memberships/orgs/{org}", func(w http.ResponseWriter, r *http.Request) {
+		switch r.PathValue("org") {
+		case "acme":
+			w.Write([]byte(`{"state": "active"}`))
+		case "pending":
+			w.Write([]byte(`{"state": "pending"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	mux.HandleFunc("GET /orgs/{org}/teams/{team}/memberships/{login}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("org") == "acme" && r.PathValue("team") == "finops" && r.PathValue("login") == "octocat" {
+			w.Write([]byte(`{"state": "active"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGitHubClient_User(t *testing.T) {
+	srv := newFakeGitHubAPI(t)
+	c := NewGitHubClient(srv.URL, srv.Client())
+	u, err := c.User(context.Background(), "good")
+	if err != nil {
+		t.Fatalf("User: %v", err)
+	}
+	if u.Login != "octocat" || u.ID != 1 {
+		t.Fatalf("user mismatch: %+v", u)
+	}
+	if _, err := c.User(context.Background(), "bad"); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("User with bad token: got %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestGitHubClient_Membership(t *testing.T) {
+	srv := newFakeGitHubAPI(t)
+	c := NewGitHubClient(srv.URL, srv.Client())
+	ctx := context.Background()
+	orgs := map[string]bool{"acme": true, "pending": false, "other": false}
+	for org, want := range orgs {
+		got, err := c.OrgMember(ctx, "good", org)
+		if err != nil {
+			t.Fatalf("OrgMember %s: %v", org, err)
+		}
+		if got != want {
+			t.Errorf("OrgMember %s = %v, want %v", org, got, want)
+		}
+	}
+	ok, err := c.TeamMember(ctx, "good", "acme", "finops", "octocat")
+	if err != nil || !ok {
+		t.Fatalf("TeamMember acme/finops = %v, %v; want true", ok, err)
+	}
+	ok, err = c.TeamMember(ctx, "good", "acme", "sre", "octocat")
+	if err != nil || ok {
+		t.Fatalf("TeamMember acme/sre = %v, %v; want false", ok, err)
+	}
+}
+
+func TestGitHubClient_RateLimited(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+	c := NewGitHubClient(srv.URL, srv.Client())
+	if _, err := c.OrgMember(context.Background(), "good", "acme"); err == nil {
+		t.Fatal("expected error when rate limited")
+	}
+}
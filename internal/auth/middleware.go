@@ -0,0 +1,236 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRecheckInterval is how long a membership decision is trusted before
+// it is checked against the GitHub API again.
+const DefaultRecheckInterval = 15 * time.Minute
+
+// GitHub is the subset of the GitHub API used by the Authenticator.
+type GitHub interface {
+	MembershipChecker
+	User(ctx context.Context, token string) (GitHubUser, error)
+}
+
+// Principal identifies the caller of an authenticated request.
+// Exactly one of Login and APIKey is set.
+type Principal struct {
+	Login  string // GitHub login of an interactive user
+	APIKey string // name of the API key used by a service
+	Role   Role
+}
+
+// String returns a stable identifier such as "github:octocat" or "apikey:ci".
+func (p Principal) String() string {
+	if p.APIKey != "" {
+		return "apikey:" + p.APIKey
+	}
+	return "github:" + p.Login
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal stored in ctx, if any.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// Authenticator validates bearer tokens. GitHub tokens are checked against
+// the Allowlist; decisions are cached per token and re-checked after the
+// recheck interval, so users removed from an organization or team lose access
+// without having to sign in again. API keys are looked up in the key store on
+// every request and are not subject to the allowlist.
+type Authenticator struct {
+	github    GitHub
+	allowlist Allowlist
+	roles     RoleBindings
+	keys      APIKeyStore
+	recheck   time.Duration
+	metrics   Metrics
+	now       func() time.Time
+
+	mu       sync.Mutex
+	sessions map[[sha256.Size]byte]session
+}
+
+type session struct {
+	principal Principal
+	allowed   bool
+	checkedAt time.Time
+}
+
+// Options configures an Authenticator.
+type Options struct {
+	// Allowlist restricts GitHub users to organization or team members.
+	Allowlist Allowlist
+	// Roles grants roles to principals.
+	Roles RoleBindings
+	// APIKeys looks up API keys; nil disables API key authentication.
+	APIKeys APIKeyStore
+	// RecheckInterval is how long membership decisions are cached;
+	// zero selects DefaultRecheckInterval.
+	RecheckInterval time.Duration
+	// Metrics counts authentication outcomes; nil disables counting.
+	Metrics Metrics
+}
+
+// Metrics receives the outcome of every authentication attempt made by
+// the middleware: "ok", "missing_token", "invalid_token", "denied" or "error".
+type Metrics interface {
+	AuthOutcome(outcome string)
+}
+
+// NewAuthenticator creates an Authenticator.
+func NewAuthenticator(github GitHub, opts Options) *Authenticator {
+	recheck := opts.RecheckInterval
+	if recheck <= 0 {
+		recheck = DefaultRecheckInterval
+	}
+	return &Authenticator{
+		github:    github,
+		allowlist: opts.Allowlist,
+		roles:     opts.Roles,
+		keys:      opts.APIKeys,
+		recheck:   recheck,
+		metrics:   opts.Metrics,
+		now:       time.Now,
+		sessions:  make(map[[sha256.Size]byte]session),
+	}
+}
+
+// ErrNotAllowed is returned when an authenticated user is not on the allowlist.
+var ErrNotAllowed = errors.New("user is not a member of an allowed GitHub organization or team")
+
+// Authenticate resolves the principal for token. GitHub users are checked against the allowlist.
+func (a *Authenticator) Authenticate(ctx context.Context, token string) (Principal, error) {
+	if IsAPIKey(token) {
+		return a.authenticateAPIKey(ctx, token)
+	}
+	key := sha256.Sum256([]byte(token))
+	now := a.now()
+	a.mu.Lock()
+	s, ok := a.sessions[key]
+	a.mu.Unlock()
+	if !ok || now.Sub(s.checkedAt) >= a.recheck {
+		var err error
+		s, err = a.check(ctx, token)
+		if err != nil {
+			a.mu.Lock()
+			delete(a.sessions, key)
+			a.mu.Unlock()
+			return Principal{}, err
+		}
+		a.store(key, s)
+	}
+	if !s.allowed {
+		return s.principal, ErrNotAllowed
+	}
+	return s.principal, nil
+}
+
+func (a *Authenticator) check(ctx context.Context, token string) (session, error) {
+	user, err := a.github.User(ctx, token)
+	if err != nil {
+		return session{}, err
+	}
+	p := Principal{Login: user.Login}
+	allowed, err := a.allowlist.Allows(ctx, a.github, token, user.Login)
+	if err != nil {
+		return session{}, fmt.Errorf("check membership for %s: %w", user.Login, err)
+	}
+	if allowed {
+		if p.Role, err = a.roles.githubRole(ctx, a.github, token, user.Login); err != nil {
+			return session{}, fmt.Errorf("resolve role for %s: %w", user.Login, err)
+		}
+	}
+	return session{principal: p, allowed: allowed, checkedAt: a.now()}, nil
+}
+
+func (a *Authenticator) store(key [sha256.Size]byte, s session) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for k, old := range a.sessions {
+		if s.checkedAt.Sub(old.checkedAt) >= a.recheck {
+			delete(a.sessions, k)
+		}
+	}
+	a.sessions[key] = s
+}
+
+// Middleware rejects requests without a valid bearer token with 401 and
+// requests from users outside the allowlist with 403. Allowed requests carry
+// the Principal, including its Role, in their context.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(r)
+		if !ok {
+			a.outcome("missing_token")
+			w.Header().Set("WWW-Authenticate", `Bearer`)
+			writeError(w, http.StatusUnauthorized, errorResponse{Error: "invalid_request", Description: "missing bearer token"})
+			return
+		}
+		p, err := a.Authenticate(r.Context(), token)
+		switch {
+		case errors.Is(err, ErrInvalidToken), errors.Is(err, ErrInvalidAPIKey):
+			a.outcome("invalid_token")
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			writeError(w, http.StatusUnauthorized, errorResponse{Error: "invalid_token", Description: err.Error()})
+			return
+		case errors.Is(err, ErrNotAllowed):
+			a.outcome("denied")
+			writeError(w, http.StatusForbidden, errorResponse{Error: "access_denied", Description: err.Error(), Login: p.Login})
+			return
+		case err != nil:
+			a.outcome("error")
+			slog.ErrorContext(r.Context(), "authenticate", "error", err)
+			writeError(w, http.StatusServiceUnavailable, errorResponse{Error: "temporarily_unavailable", Description: "unable to verify credentials"})
+			return
+		}
+		a.outcome("ok")
+		next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+	})
+}
+
+func (a *Authenticator) outcome(outcome string) {
+	if a.metrics != nil {
+		a.metrics.AuthOutcome(outcome)
+	}
+}
+
+type errorResponse struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+	Login       string `json:"login,omitempty"`
+}
+
+func writeError(w http.ResponseWriter, status int, body errorResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
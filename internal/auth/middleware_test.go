@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fakeGitHub struct {
+	members map[string]bool
+	calls   int
+}
+
+func (f *fakeGitHub) User(ctx context.Context, token string) (GitHubUser, error) {
+	f.calls++
+	if token != "good" {
+		return GitHubUser{}, ErrInvalidToken
+	}
+	return GitHubUser{ID: 1, Login: "octocat"}, nil
+}
+
+func (f *fakeGitHub) OrgMember(ctx context.Context, token, org string) (bool, error) {
+	return f.members[org], nil
+}
+
+func (f *fakeGitHub) TeamMember(ctx context.Context, token, org, team, login string) (bool, error) {
+	return f.members[org+"/"+team], nil
+}
+
+func TestParseAllowlist(t *testing.T) {
+	a, err := ParseAllowlist("acme, widgets,", "acme/finops")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(a.Orgs) != 2 || a.Orgs[1] != "widgets" {
+		t.Fatalf("orgs = %v", a.Orgs)
+	}
+	if len(a.Teams) != 1 || a.Teams[0].String() != "acme/finops" {
+		t.Fatalf("teams = %v", a.Teams)
+	}
+	if _, err := ParseAllowlist("", "finops"); err == nil {
+		t.Fatal("expected error for team without org")
+	}
+	empty, err := ParseAllowlist("", "")
+	if err != nil || !empty.Empty() {
+		t.Fatalf("empty allowlist = %+v, %v", empty, err)
+	}
+}
+
+func TestAuthenticator_Middleware(t *testing.T) {
+	gh := &fakeGitHub{members: map[string]bool{"acme/finops": true}}
+	allow := Allowlist{Orgs: []string{"acme"}, Teams: []Team{{Org: "acme", Slug: "finops"}}}
+	outcomes := outcomeCounter{}
+	a := NewAuthenticator(gh, Options{Allowlist: allow, Metrics: outcomes})
+	var got Principal
+	h := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = PrincipalFromContext(r.Context())
+	}))
+
+	do := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("missing token: status %d, want 401", rec.Code)
+	}
+	if rec := do("bad"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("bad token: status %d, want 401", rec.Code)
+	}
+	if rec := do("good"); rec.Code != http.StatusOK {
+		t.Fatalf("allowed user: status %d, want 200", rec.Code)
+	}
+	if got.Login != "octocat" {
+		t.Fatalf("principal = %+v", got)
+	}
+	if outcomes["missing_token"] != 1 || outcomes["invalid_token"] != 1 || outcomes["ok"] != 1 {
+		t.Fatalf("outcomes = %v", outcomes)
+	}
+}
+
+type outcomeCounter map[string]int
+
+func (c outcomeCounter) AuthOutcome(outcome string) { c[outcome]++ }
+
+func TestAuthenticator_DeniedPayload(t *testing.T) {
+	a := NewAuthenticator(&fakeGitHub{}, Options{Allowlist: Allowlist{Orgs: []string{"acme"}}})
+	h := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler must not be called for denied users")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer good")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status %d, want 403", rec.Code)
+	}
+	var body errorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Error != "access_denied" || body.Login != "octocat" || body.Description == "" {
+		t.Fatalf("payload = %+v", body)
+	}
+}
+
+func TestAuthenticator_Recheck(t *testing.T) {
+	gh := &fakeGitHub{members: map[string]bool{"acme": true}}
+	a := NewAuthenticator(gh, Options{Allowlist: Allowlist{Orgs: []string{"acme"}}, RecheckInterval: time.Minute})
+	now := time.Unix(0, 0)
+	a.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := a.Authenticate(ctx, "good"); err != nil {
+		t.Fatalf("first: %v", err)
+	}
+	gh.members["acme"] = false
+	if _, err := a.Authenticate(ctx, "good"); err != nil {
+		t.Fatalf("cached: %v", err)
+	}
+	if gh.calls != 1 {
+		t.Fatalf("github calls = %d, want 1", gh.calls)
+	}
+	now = now.Add(2 * time.Minute)
+	if _, err := a.Authenticate(ctx, "good"); err != ErrNotAllowed {
+		t.Fatalf("after recheck: got %v, want ErrNotAllowed", err)
+	}
+}
+
+func TestAllowlist_EmptyAllowsEveryone(t *testing.T) {
+	a := NewAuthenticator(&fakeGitHub{}, Options{})
+	if _, err := a.Authenticate(context.Background(), "good"); err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Role is an access level granted to a principal.
+type Role string
+
+const (
+	// RoleUser may call the pricing tools.
+	RoleUser Role = "user"
+	// RoleAdmin may additionally run operational tools and routes such as
+	// triggering a sync, reading audit logs or managing API keys.
+	RoleAdmin Role = "admin"
+)
+
+var roleRank = map[Role]int{RoleUser: 1, RoleAdmin: 2}
+
+// Satisfies reports whether r grants at least the access of required.
+func (r Role) Satisfies(required Role) bool {
+	return roleRank[r] >= roleRank[required] && roleRank[r] > 0
+}
+
+// ParseRole validates a role name.
+func ParseRole(s string) (Role, error) {
+	r := Role(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := roleRank[r]; !ok {
+		return "", fmt.Errorf("unknown role %q: want %q or %q", s, RoleUser, RoleAdmin)
+	}
+	return r, nil
+}
+
+// RoleBindings grants roles to principals. GitHub users receive the highest
+// role bound to their login or to any organization or team ("org/team-slug")
+// they belong to; API keys receive the role bound to their name. Principals
+// without a binding have RoleUser.
+type RoleBindings struct {
+	Users   map[string]Role `json:"users,omitempty"`
+	Orgs    map[string]Role `json:"orgs,omitempty"`
+	Teams   map[string]Role `json:"teams,omitempty"`
+	APIKeys map[string]Role `json:"api_keys,omitempty"`
+}
+
+// LoadRoleBindings reads role bindings from a JSON file such as
+//
+//	{"users": {"octocat": "admin"}, "teams": {"acme/finops": "admin"}, "api_keys": {"ci": "user"}}
+func LoadRoleBindings(path string) (RoleBindings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return RoleBindings{}, fmt.Errorf("read role bindings: %w", err)
+	}
+	var b RoleBindings
+	if err := json.Unmarshal(data, &b); err != nil {
+		return RoleBindings{}, fmt.Errorf("parse role bindings %s: %w", path, err)
+	}
+	if err := b.normalize(); err != nil {
+		return RoleBindings{}, fmt.Errorf("role bindings %s: %w", path, err)
+	}
+	return b, nil
+}
+
+// normalize validates every role and team reference, rewriting roles to canonical form.
+func (b RoleBindings) normalize() error {
+	for kind, m := range map[string]map[string]Role{"users": b.Users, "orgs": b.Orgs, "teams": b.Teams, "api_keys": b.APIKeys} {
+		for name, role := range m {
+			r, err := ParseRole(string(role))
+			if err != nil {
+				return fmt.Errorf("%s %q: %w", kind, name, err)
+			}
+			m[name] = r
+		}
+	}
+	for ref := range b.Teams {
+		if _, err := parseTeam(ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// githubRole resolves the role of a GitHub user, querying membership only for
+// organizations and teams whose binding would raise the role.
+func (b RoleBindings) githubRole(ctx context.Context, gh MembershipChecker, token, login string) (Role, error) {
+	role := RoleUser
+	if r, ok := b.Users[login]; ok && !role.Satisfies(r) {
+		role = r
+	}
+	for org, r := range b.Orgs {
+		if role.Satisfies(r) {
+			continue
+		}
+		ok, err := gh.OrgMember(ctx, token, org)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			role = r
+		}
+	}
+	for ref, r := range b.Teams {
+		if role.Satisfies(r) {
+			continue
+		}
+		t, _ := parseTeam(ref)
+		ok, err := gh.TeamMember(ctx, token, t.Org, t.Slug, login)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			role = r
+		}
+	}
+	return role, nil
+}
+
+func (b RoleBindings) apiKeyRole(name string) Role {
+	if r, ok := b.APIKeys[name]; ok {
+		return r
+	}
+	return RoleUser
+}
+
+// RequireRole rejects requests whose principal does not hold role with 403.
+// It must run after Authenticator.Middleware.
+func RequireRole(role Role, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, ok := PrincipalFromContext(r.Context())
+		if !ok || !p.Role.Satisfies(role) {
+			writeError(w, http.StatusForbidden, errorResponse{
+				Error:       "insufficient_role",
+				Description: fmt.Sprintf("the %s role is required", role),
+				Login:       p.Login,
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRole_Satisfies(t *testing.T) {
+	cases := []struct {
+		have, need Role
+		want       bool
+	}{
+		{RoleAdmin, RoleUser, true},
+		{RoleAdmin, RoleAdmin, true},
+		{RoleUser, RoleUser, true},
+		{RoleUser, RoleAdmin, false},
+		{"", RoleUser, false},
+	}
+	for _, c := range cases {
+		if got := c.have.Satisfies(c.need); got != c.want {
+			t.Errorf("%q.Satisfies(%q) = %v, want %v", c.have, c.need, got, c.want)
+		}
+	}
+}
+
+func TestLoadRoleBindings(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "roles.json")
+	if err := os.WriteFile(path, []byte(`{"users": {"octocat": "Admin"}, "teams": {"acme/finops": "admin"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	b, err := LoadRoleBindings(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if b.Users["octocat"] != RoleAdmin || b.Teams["acme/finops"] != RoleAdmin {
+		t.Fatalf("bindings = %+v", b)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte(`{"orgs": {"acme": "root"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRoleBindings(bad); err == nil {
+		t.Fatal("expected error for unknown role")
+	}
+}
+
+func TestAuthenticator_Roles(t *testing.T) {
+	gh := &fakeGitHub{members: map[string]bool{"acme": true, "acme/finops": true}}
+	roles := RoleBindings{
+		Teams:   map[string]Role{"acme/finops": RoleAdmin},
+		APIKeys: map[string]Role{"ops": RoleAdmin},
+	}
+	a := NewAuthenticator(gh, Options{Roles: roles})
+	p, err := a.Authenticate(context.Background(), "good")
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if p.Role != RoleAdmin {
+		t.Fatalf("team member role = %q, want admin", p.Role)
+	}
+
+	gh.members["acme/finops"] = false
+	a = NewAuthenticator(gh, Options{Roles: roles})
+	if p, _ := a.Authenticate(context.Background(), "good"); p.Role != RoleUser {
+		t.Fatalf("non-member role = %q, want user", p.Role)
+	}
+	if got := roles.apiKeyRole("ops"); got != RoleAdmin {
+		t.Fatalf("api key role = %q, want admin", got)
+	}
+	if got := roles.apiKeyRole("ci"); got != RoleUser {
+		t.Fatalf("unbound api key role = %q, want user", got)
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	h := RequireRole(RoleAdmin, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for _, c := range []struct {
+		p    Principal
+		want int
+	}{
+		{Principal{Login: "alice", Role: RoleAdmin}, http.StatusOK},
+		{Principal{Login: "bob", Role: RoleUser}, http.StatusForbidden},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(WithPrincipal(req.Context(), c.p))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != c.want {
+			t.Errorf("%s: status %d, want %d", c.p.Login, rec.Code, c.want)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+// Package buildinfo reports the version the binaries were built as.
+package buildinfo
+
+import "runtime/debug"
+
+// version is set at build time with
+// -ldflags "-X mcp-server/internal/buildinfo.version=...".
+var version = "dev"
+
+// Version returns the build version, suffixed with the VCS revision when the
+// binary was built from a repository checkout.
+func Version() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return version
+	}
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" && len(s.Value) >= 12 {
+			return version + "+" + s.Value[:12]
+		}
+	}
+	return version
+}
@@ -0,0 +1,12 @@
+package buildinfo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVersion(t *testing.T) {
+	if v := Version(); !strings.HasPrefix(v, version) {
+		t.Fatalf("Version() = %q, want prefix %q", v, version)
+	}
+}
@@ -0,0 +1,217 @@
+// Package cache keeps hot catalog lookups in memory so repeated SKU details
+// and pricing requests do not each round-trip to Turso.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"mcp-server/internal/database"
+)
+
+// Store is the catalog the cache reads through to.
+type Store interface {
+	SKUByID(ctx context.Context, skuID string) (database.SKU, error)
+	LatestPricingInfo(ctx context.Context, skuID string) (database.PricingInfo, error)
+	LatestUpdateID(ctx context.Context) (int64, error)
+}
+
+var _ Store = (*database.SQLRepository)(nil)
+
+// Metrics counts cache lookups by kind, such as "sku" or "pricing".
+type Metrics interface {
+	CacheLookup(kind string, hit bool)
+}
+
+// Options configures a Cache. Zero fields take the defaults below.
+type Options struct {
+	// Size bounds the number of entries of all kinds; the least recently
+	// used entry is evicted first. Default 10000.
+	Size int
+	// TTL bounds how long an entry is served. Default 10 minutes.
+	TTL time.Duration
+	// CheckInterval is how often pricing_updates is polled for a finished
+	// sync, which empties the cache. Default 30 seconds.
+	CheckInterval time.Duration
+	// Metrics counts hits and misses; nil disables them.
+	Metrics Metrics
+}
+
+const (
+	defaultSize          = 10000
+	defaultTTL           = 10 * time.Minute
+	defaultCheckInterval = 30 * time.Second
+)
+
+type key struct {
+	kind string
+	id   string
+}
+
+type entry struct {
+	key     key
+	value   any
+	expires time.Time
+}
+
+// Cache is an LRU cache with per-entry expiry in front of a Store. Errors,
+// including sql.ErrNoRows, are not cached. Cached values are shared between
+// callers and must not be modified.
+type Cache struct {
+	store         Store
+	size          int
+	ttl           time.Duration
+	checkInterval time.Duration
+	metrics       Metrics
+	now           func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List // front is most recently used
+	entries map[key]*list.Element
+	gen     uint64 // incremented on every purge
+
+	checkMu   sync.Mutex
+	checkedAt time.Time
+	updateID  int64
+}
+
+// New creates a Cache reading through to store.
+func New(store Store, opts Options) *Cache {
+	c := &Cache{
+		store:         store,
+		size:          opts.Size,
+		ttl:           opts.TTL,
+		checkInterval: opts.CheckInterval,
+		metrics:       opts.Metrics,
+		now:           time.Now,
+		order:         list.New(),
+		entries:       make(map[key]*list.Element),
+	}
+	if c.size <= 0 {
+		c.size = defaultSize
+	}
+	if c.ttl <= 0 {
+		c.ttl = defaultTTL
+	}
+	if c.checkInterval <= 0 {
+		c.checkInterval = defaultCheckInterval
+	}
+	if c.metrics == nil {
+		c.metrics = nopMetrics{}
+	}
+	return c
+}
+
+// SKUByID returns the SKU with the given ID.
+func (c *Cache) SKUByID(ctx context.Context, skuID string) (database.SKU, error) {
+	return get(c, ctx, key{"sku", skuID}, c.store.SKUByID)
+}
+
+// LatestPricingInfo returns the newest pricing of a SKU.
+func (c *Cache) LatestPricingInfo(ctx context.Context, skuID string) (database.PricingInfo, error) {
+	return get(c, ctx, key{"pricing", skuID}, c.store.LatestPricingInfo)
+}
+
+// Len returns the number of cached entries, including expired ones not yet
+// evicted.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func get[V any](c *Cache, ctx context.Context, k key, load func(context.Context, string) (V, error)) (V, error) {
+	c.checkUpdates(ctx)
+	if v, ok := c.lookup(k); ok {
+		c.metrics.CacheLookup(k.kind, true)
+		return v.(V), nil
+	}
+	c.metrics.CacheLookup(k.kind, false)
+	c.mu.Lock()
+	gen := c.gen
+	c.mu.Unlock()
+	v, err := load(ctx, k.id)
+	if err != nil {
+		return v, err
+	}
+	c.add(k, v, gen)
+	return v, nil
+}
+
+func (c *Cache) lookup(k key) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[k]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if !c.now().Before(e.expires) {
+		c.remove(el)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return e.value, true
+}
+
+// add caches v unless the cache was purged since the load started, in which
+// case v may predate the sync that caused the purge.
+func (c *Cache) add(k key, v any, gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.gen {
+		return
+	}
+	if el, ok := c.entries[k]; ok {
+		c.remove(el)
+	}
+	c.entries[k] = c.order.PushFront(&entry{key: k, value: v, expires: c.now().Add(c.ttl)})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *Cache) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*entry).key)
+}
+
+// Purge empties the cache.
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	clear(c.entries)
+	c.gen++
+}
+
+// checkUpdates purges the cache when a sync finished since the last check.
+// At most one lookup per interval pays for the query while the others carry
+// on; a failed check keeps serving cached entries until they expire.
+func (c *Cache) checkUpdates(ctx context.Context) {
+	if !c.checkMu.TryLock() {
+		return
+	}
+	defer c.checkMu.Unlock()
+	now := c.now()
+	if !c.checkedAt.IsZero() && now.Sub(c.checkedAt) < c.checkInterval {
+		return
+	}
+	c.checkedAt = now
+	id, err := c.store.LatestUpdateID(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "check for pricing updates", "error", err)
+		return
+	}
+	if id != c.updateID {
+		c.updateID = id
+		c.Purge()
+	}
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) CacheLookup(string, bool) {}
@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"mcp-server/internal/database"
+)
+
+type fakeStore struct {
+	skuLoads, priceLoads, checks int
+	updateID                     int64
+	description                  string
+}
+
+func (f *fakeStore) SKUByID(ctx context.Context, skuID string) (database.SKU, error) {
+	f.skuLoads++
+	if skuID == "missing" {
+		return database.SKU{}, sql.ErrNoRows
+	}
+	return database.SKU{SKUID: skuID, Description: f.description}, nil
+}
+
+func (f *fakeStore) LatestPricingInfo(ctx context.Context, skuID string) (database.PricingInfo, error) {
+	f.priceLoads++
+	return database.PricingInfo{SKUID: skuID, UsageUnit: "h"}, nil
+}
+
+func (f *fakeStore) LatestUpdateID(ctx context.Context) (int64, error) {
+	f.checks++
+	return f.updateID, nil
+}
+
+type fakeMetrics struct{ hits, misses map[string]int }
+
+func (m *fakeMetrics) CacheLookup(kind string, hit bool) {
+	if hit {
+		m.hits[kind]++
+	} else {
+		m.misses[kind]++
+	}
+}
+
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func newTestCache(store Store, opts Options) (*Cache, *clock) {
+	c := New(store, opts)
+	clk := &clock{t: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c.now = clk.now
+	return c, clk
+}
+
+func TestCache_HitsAndMisses(t *testing.T) {
+	store := &fakeStore{description: "v1"}
+	m := &fakeMetrics{hits: map[string]int{}, misses: map[string]int{}}
+	c, _ := newTestCache(store, Options{Metrics: m})
+	ctx := context.Background()
+	for range 3 {
+		if sku, err := c.SKUByID(ctx, "a"); err != nil || sku.Description != "v1" {
+			t.Fatalf("SKUByID = %+v, %v", sku, err)
+		}
+		if _, err := c.LatestPricingInfo(ctx, "a"); err != nil {
+			t.Fatalf("LatestPricingInfo: %v", err)
+		}
+	}
+	if store.skuLoads != 1 || store.priceLoads != 1 {
+		t.Fatalf("loads = %d SKU, %d pricing; want 1 each", store.skuLoads, store.priceLoads)
+	}
+	if m.hits["sku"] != 2 || m.misses["sku"] != 1 || m.hits["pricing"] != 2 || m.misses["pricing"] != 1 {
+		t.Fatalf("hits %v, misses %v", m.hits, m.misses)
+	}
+}
+
+func TestCache_ErrorsNotCached(t *testing.T) {
+	store := &fakeStore{}
+	c, _ := newTestCache(store, Options{})
+	for range 2 {
+		if _, err := c.SKUByID(context.Background(), "missing"); !errors.Is(err, sql.ErrNoRows) {
+			t.Fatalf("got %v, want sql.ErrNoRows", err)
+		}
+	}
+	if store.skuLoads != 2 || c.Len() != 0 {
+		t.Fatalf("loads %d, len %d; want 2 loads and nothing cached", store.skuLoads, c.Len())
+	}
+}
+
+func TestCache_SizeBound(t *testing.T) {
+	store := &fakeStore{}
+	c, _ := newTestCache(store, Options{Size: 2})
+	ctx := context.Background()
+	for _, id := range []string{"a", "b", "a", "c"} {
+		c.SKUByID(ctx, id)
+	}
+	if c.Len() != 2 {
+		t.Fatalf("len = %d, want 2", c.Len())
+	}
+	// b was least recently used when c was added, so only b is reloaded.
+	c.SKUByID(ctx, "a")
+	c.SKUByID(ctx, "c")
+	c.SKUByID(ctx, "b")
+	if store.skuLoads != 4 {
+		t.Fatalf("loads = %d, want 4", store.skuLoads)
+	}
+}
+
+func TestCache_TTL(t *testing.T) {
+	store := &fakeStore{}
+	c, clk := newTestCache(store, Options{TTL: time.Minute, CheckInterval: time.Hour})
+	ctx := context.Background()
+	c.SKUByID(ctx, "a")
+	clk.t = clk.t.Add(59 * time.Second)
+	c.SKUByID(ctx, "a")
+	clk.t = clk.t.Add(time.Second)
+	c.SKUByID(ctx, "a")
+	if store.skuLoads != 2 {
+		t.Fatalf("loads = %d, want 2", store.skuLoads)
+	}
+}
+
+func TestCache_InvalidatedBySync(t *testing.T) {
+	store := &fakeStore{updateID: 1, description: "v1"}
+	c, clk := newTestCache(store, Options{CheckInterval: 30 * time.Second})
+	ctx := context.Background()
+	c.SKUByID(ctx, "a")
+
+	store.updateID, store.description = 2, "v2"
+	clk.t = clk.t.Add(10 * time.Second)
+	if sku, _ := c.SKUByID(ctx, "a"); sku.Description != "v1" {
+		t.Fatalf("before the next check got %q, want cached v1", sku.Description)
+	}
+	clk.t = clk.t.Add(20 * time.Second)
+	if sku, _ := c.SKUByID(ctx, "a"); sku.Description != "v2" {
+		t.Fatalf("after a sync got %q, want v2", sku.Description)
+	}
+	if store.checks != 2 {
+		t.Fatalf("checks = %d, want 2", store.checks)
+	}
+}
@@ -0,0 +1,194 @@
+package compute
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"mcp-server/internal/database"
+	"mcp-server/internal/pricing"
+)
+
+// HoursPerMonth is the average number of hours in a month used by Google
+// Cloud pricing.
+const HoursPerMonth = 730
+
+// Catalog finds SKUs and their current pricing.
+type Catalog interface {
+	SearchSKUs(ctx context.Context, f database.SKUFilter) ([]database.SKU, error)
+	LatestPricingInfo(ctx context.Context, skuID string) (database.PricingInfo, error)
+}
+
+// Disk is a persistent disk attached to a VM.
+type Disk struct {
+	Type   string // such as "pd-balanced"
+	SizeGB float64
+}
+
+// VM describes a VM configuration. Memory and disk sizes are in GB as
+// Compute Engine documents them, which are billed as GiB.
+type VM struct {
+	Family string
+	VCPUs  int
+	// BilledVCPUs, when set, is the vCPUs billed, fewer than VCPUs for
+	// shared-core machine types.
+	BilledVCPUs float64
+	MemoryGB    float64
+	Region      string
+	Hours       float64 // hours the VM runs per month
+	Spot        bool
+	Disks       []Disk
+	// GPUs of GPUType and local SSD come with some machine types.
+	GPUs       int
+	GPUType    string
+	LocalSSDGB float64
+}
+
+// Line is the monthly cost of one billed resource of a VM, or a discount on
+// one as a negative cost.
+type Line struct {
+	Item        string // "vcpu", "memory", "gpu <type>", "local ssd", "disk <type>" or "sustained use discount <item>"
+	SKUID       string
+	Description string
+	Quantity    float64
+	UsageUnit   string
+	UnitPrice   float64
+	Cost        float64
+}
+
+// Estimate is the itemized monthly cost of a VM.
+type Estimate struct {
+	Currency string
+	Lines    []Line
+	Total    float64
+}
+
+// EstimateVM resolves the vCPU, memory, GPU and disk SKUs of vm in its region
+// and prices a month of use. Local SSD and persistent disks are billed for
+// the whole month whatever the running hours. Sustained use discounts earned
+// by the running hours follow the line they apply to as negative lines.
+func EstimateVM(ctx context.Context, c Catalog, vm VM) (Estimate, error) {
+	family, ok := FamilyByName(strings.ToLower(vm.Family))
+	if !ok {
+		return Estimate{}, fmt.Errorf("unknown machine family %q, want one of %s", vm.Family, strings.Join(FamilyNames(), ", "))
+	}
+	switch {
+	case vm.VCPUs <= 0:
+		return Estimate{}, errors.New("vcpus must be positive")
+	case vm.MemoryGB <= 0:
+		return Estimate{}, errors.New("memory_gb must be positive")
+	case vm.Hours <= 0 || vm.Hours > 744:
+		return Estimate{}, fmt.Errorf("hours per month %v must be between 0 and 744", vm.Hours)
+	case vm.Region == "":
+		return Estimate{}, errors.New("region is required")
+	}
+	vcpus := float64(vm.VCPUs)
+	if vm.BilledVCPUs > 0 {
+		vcpus = vm.BilledVCPUs
+	}
+	prefix := ""
+	if vm.Spot {
+		prefix = data.SpotPrefix
+	}
+	items := []item{
+		{"vcpu", prefix + family.CPUDescription + " running in *", "h", vcpus * vm.Hours},
+		{"memory", prefix + family.RAMDescription + " running in *", "GiBy.h", vm.MemoryGB * vm.Hours},
+	}
+	if vm.GPUs > 0 {
+		a, ok := AcceleratorByType(vm.GPUType)
+		if !ok {
+			return Estimate{}, fmt.Errorf("unknown GPU type %q", vm.GPUType)
+		}
+		description := a.Description
+		if vm.Spot {
+			description = a.SpotDescription
+		}
+		items = append(items, item{"gpu " + a.Type, description + " running in *", "h", float64(vm.GPUs) * vm.Hours})
+	}
+	if vm.LocalSSDGB > 0 {
+		description := data.LocalSSD.Description
+		if vm.Spot {
+			description = data.LocalSSD.SpotDescription
+		}
+		items = append(items, item{"local ssd", description + " in *", "GiBy.mo", vm.LocalSSDGB})
+	}
+	for _, d := range vm.Disks {
+		t, ok := DiskTypeByName(d.Type)
+		if !ok {
+			return Estimate{}, fmt.Errorf("unknown disk type %q, want one of %s", d.Type, strings.Join(DiskTypeNames(), ", "))
+		}
+		if d.SizeGB <= 0 {
+			return Estimate{}, fmt.Errorf("%s disk size must be positive", d.Type)
+		}
+		items = append(items, item{"disk " + d.Type, t.Description + "*", "GiBy.mo", d.SizeGB})
+	}
+
+	var est Estimate
+	for _, it := range items {
+		sku, p, err := resolve(ctx, c, it.pattern, vm.Region)
+		if err != nil {
+			return Estimate{}, fmt.Errorf("%s: %w", it.name, err)
+		}
+		if p.UsageUnit != it.unit {
+			return Estimate{}, fmt.Errorf("%s: SKU %s is priced per %s, want %s", it.name, sku.SKUID, p.UsageUnit, it.unit)
+		}
+		cost, err := pricing.Calculate(p, it.quantity)
+		if err != nil {
+			return Estimate{}, fmt.Errorf("%s: %w", it.name, err)
+		}
+		est.Currency = p.CurrencyCode
+		est.Lines = append(est.Lines, Line{
+			Item:        it.name,
+			SKUID:       sku.SKUID,
+			Description: sku.Description,
+			Quantity:    it.quantity,
+			UsageUnit:   p.UsageUnit,
+			UnitPrice:   pricing.BaseUnitPrice(p),
+			Cost:        cost.Total,
+		})
+		est.Total += cost.Total
+		if family, tiers, ok := SustainedUseTiers(sku); ok {
+			discount := cost.Total * (1 - pricing.SustainedUseMultiplier(tiers, vm.Hours/HoursPerMonth))
+			if discount > 0 {
+				est.Lines = append(est.Lines, Line{
+					Item:        "sustained use discount " + it.name,
+					SKUID:       sku.SKUID,
+					Description: fmt.Sprintf("%s sustained use discount for running %.0f of %d hours", family, vm.Hours, HoursPerMonth),
+					Cost:        -discount,
+				})
+				est.Total -= discount
+			}
+		}
+	}
+	return est, nil
+}
+
+// item is a resource of a VM: the pattern its SKU description matches, the
+// usage unit it must be priced in and the quantity used in a month.
+type item struct {
+	name, pattern, unit string
+	quantity            float64
+}
+
+// resolve finds the one SKU offered in region whose description matches
+// pattern, and its pricing.
+func resolve(ctx context.Context, c Catalog, pattern, region string) (database.SKU, database.PricingInfo, error) {
+	skus, err := c.SearchSKUs(ctx, database.SKUFilter{DescriptionPattern: pattern, Region: region, Limit: 10})
+	if err != nil {
+		return database.SKU{}, database.PricingInfo{}, err
+	}
+	switch len(skus) {
+	case 0:
+		return database.SKU{}, database.PricingInfo{}, fmt.Errorf("no SKU matching %q is offered in %s", pattern, region)
+	case 1:
+	default:
+		return database.SKU{}, database.PricingInfo{}, fmt.Errorf("%d SKUs matching %q are offered in %s", len(skus), pattern, region)
+	}
+	p, err := c.LatestPricingInfo(ctx, skus[0].SKUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.SKU{}, database.PricingInfo{}, fmt.Errorf("SKU %s has no pricing", skus[0].SKUID)
+	}
+	return skus[0], p, err
+}
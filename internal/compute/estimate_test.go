@@ -0,0 +1,159 @@
+package compute
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+
+	"mcp-server/internal/database"
+)
+
+// fakeCatalog matches description patterns like the database does, with *
+// as the only wildcard.
+type fakeCatalog struct {
+	skus   []database.SKU
+	prices map[string]database.PricingInfo
+}
+
+func (f fakeCatalog) SearchSKUs(ctx context.Context, filter database.SKUFilter) ([]database.SKU, error) {
+	var out []database.SKU
+	for _, s := range f.skus {
+		if ok, _ := path.Match(filter.DescriptionPattern, s.Description); ok && slices.Contains(s.ServiceRegions, filter.Region) {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (f fakeCatalog) LatestPricingInfo(ctx context.Context, skuID string) (database.PricingInfo, error) {
+	p, ok := f.prices[skuID]
+	if !ok {
+		return database.PricingInfo{}, sql.ErrNoRows
+	}
+	return p, nil
+}
+
+func price(unit string, nanos int32) database.PricingInfo {
+	return database.PricingInfo{CurrencyCode: "USD", UsageUnit: unit,
+		TieredRates: []database.TieredRate{{UnitPrice: database.Money{Nanos: nanos}}}}
+}
+
+func testCatalog() fakeCatalog {
+	us := []string{"us-central1"}
+	return fakeCatalog{
+		skus: []database.SKU{
+			{SKUID: "cpu", Description: "N2 Instance Core running in Americas", ServiceRegions: us},
+			{SKUID: "ram", Description: "N2 Instance Ram running in Americas", ServiceRegions: us},
+			{SKUID: "spot-cpu", Description: "Spot Preemptible N2 Instance Core running in Americas", ServiceRegions: us},
+			{SKUID: "spot-ram", Description: "Spot Preemptible N2 Instance Ram running in Americas", ServiceRegions: us},
+			{SKUID: "pd", Description: "Balanced PD Capacity in Iowa", ServiceRegions: us},
+		},
+		prices: map[string]database.PricingInfo{
+			"cpu":      price("h", 30_000_000),
+			"ram":      price("GiBy.h", 4_000_000),
+			"spot-cpu": price("h", 10_000_000),
+			"spot-ram": price("GiBy.h", 1_000_000),
+			"pd":       price("GiBy.mo", 100_000_000),
+		},
+	}
+}
+
+func TestEstimateVM(t *testing.T) {
+	cat := testCatalog()
+	ctx := context.Background()
+	vm := VM{Family: "N2", VCPUs: 4, MemoryGB: 16, Region: "us-central1", Hours: HoursPerMonth,
+		Disks: []Disk{{Type: "pd-balanced", SizeGB: 100}}}
+	est, err := EstimateVM(ctx, cat, vm)
+	if err != nil {
+		t.Fatalf("EstimateVM: %v", err)
+	}
+	// 4*730*0.03 + 16*730*0.004 + 100*0.1
+	if len(est.Lines) != 3 || est.Currency != "USD" || math.Abs(est.Total-(87.6+46.72+10)) > 1e-9 {
+		t.Fatalf("estimate = %+v", est)
+	}
+	if l := est.Lines[1]; l.Item != "memory" || l.SKUID != "ram" || l.Quantity != 16*730 || l.UnitPrice != 0.004 {
+		t.Fatalf("memory line = %+v", l)
+	}
+
+	vm.Spot, vm.Hours, vm.Disks = true, 100, nil
+	est, err = EstimateVM(ctx, cat, vm)
+	if err != nil {
+		t.Fatalf("EstimateVM spot: %v", err)
+	}
+	if est.Lines[0].SKUID != "spot-cpu" || math.Abs(est.Total-(4+1.6)) > 1e-9 {
+		t.Fatalf("spot estimate = %+v", est)
+	}
+}
+
+func TestEstimateVM_Errors(t *testing.T) {
+	cat := testCatalog()
+	cat.skus = append(cat.skus, database.SKU{SKUID: "ram2", Description: "N2 Instance Ram running in Iowa", ServiceRegions: []string{"us-central1"}})
+	base := VM{Family: "n2", VCPUs: 2, MemoryGB: 8, Region: "us-central1", Hours: HoursPerMonth}
+	for _, tt := range []struct {
+		change func(*VM)
+		want   string
+	}{
+		{func(vm *VM) { vm.Family = "z9" }, "unknown machine family"},
+		{func(vm *VM) { vm.VCPUs = 0 }, "vcpus"},
+		{func(vm *VM) { vm.Hours = 800 }, "between 0 and 744"},
+		{func(vm *VM) { vm.Disks = []Disk{{Type: "floppy", SizeGB: 1}} }, "unknown disk type"},
+		{func(vm *VM) { vm.Region = "europe-west1" }, "no SKU matching"},
+		{func(vm *VM) {}, "2 SKUs matching"},
+	} {
+		vm := base
+		tt.change(&vm)
+		if _, err := EstimateVM(context.Background(), cat, vm); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("EstimateVM(%+v): got %v, want error containing %q", vm, err, tt.want)
+		}
+	}
+}
+
+func TestEstimateVM_GPUAndLocalSSD(t *testing.T) {
+	cat := testCatalog()
+	us := []string{"us-central1"}
+	cat.skus = append(cat.skus,
+		database.SKU{SKUID: "gpu", Description: "Nvidia L4 GPU running in Americas", ServiceRegions: us},
+		database.SKU{SKUID: "spot-gpu", Description: "Nvidia L4 GPU attached to Spot Preemptible VMs running in Americas", ServiceRegions: us},
+		database.SKU{SKUID: "lssd", Description: "SSD backed Local Storage in Americas", ServiceRegions: us},
+	)
+	cat.prices["gpu"] = price("h", 500_000_000)
+	cat.prices["spot-gpu"] = price("h", 200_000_000)
+	cat.prices["lssd"] = price("GiBy.mo", 80_000_000)
+	vm := VM{Family: "n2", VCPUs: 4, MemoryGB: 16, Region: "us-central1", Hours: 100, GPUs: 2, GPUType: "nvidia-l4", LocalSSDGB: 375}
+	est, err := EstimateVM(context.Background(), cat, vm)
+	if err != nil {
+		t.Fatalf("EstimateVM: %v", err)
+	}
+	if len(est.Lines) != 4 || est.Lines[2].Item != "gpu nvidia-l4" || est.Lines[2].Cost != 100 || est.Lines[3].Cost != 30 {
+		t.Fatalf("estimate = %+v", est)
+	}
+	vm.Spot, vm.LocalSSDGB = true, 0
+	if est, err := EstimateVM(context.Background(), cat, vm); err != nil || est.Lines[2].SKUID != "spot-gpu" {
+		t.Fatalf("spot estimate = %+v, %v", est, err)
+	}
+}
+
+func TestEstimateVM_SustainedUse(t *testing.T) {
+	cat := testCatalog()
+	onDemand := database.Category{ResourceFamily: "Compute", UsageType: "OnDemand"}
+	cat.skus[0].Category = onDemand
+	cat.skus[1].Category = onDemand
+	est, err := EstimateVM(context.Background(), cat, VM{Family: "n2", VCPUs: 4, MemoryGB: 16, Region: "us-central1", Hours: HoursPerMonth})
+	if err != nil {
+		t.Fatalf("EstimateVM: %v", err)
+	}
+	// A full month of N2 earns about 20%: (1 + 0.8678 + 0.7356 + 0.6034) / 4.
+	list := 87.6 + 46.72
+	if len(est.Lines) != 4 || est.Lines[1].Item != "sustained use discount vcpu" || math.Abs(est.Total-list*0.8017) > 1e-9 {
+		t.Fatalf("estimate = %+v", est)
+	}
+
+	est, err = EstimateVM(context.Background(), cat, VM{Family: "n2", VCPUs: 4, MemoryGB: 16, Region: "us-central1", Hours: 100})
+	if err != nil || len(est.Lines) != 2 {
+		t.Fatalf("estimate below the first threshold = %+v, %v", est, err)
+	}
+}
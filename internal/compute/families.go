@@ -0,0 +1,105 @@
+// Package compute estimates the cost of Compute Engine VMs from the SKUs
+// their vCPUs, memory and disks are billed under.
+package compute
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// families.json maps machine families and disk types to the descriptions of
+// their SKUs, which the catalog offers no structured way to find.
+//
+//go:embed families.json
+var familiesJSON []byte
+
+// Family names the vCPU and memory SKUs of a machine family. Regional SKU
+// descriptions continue with " running in <location>".
+type Family struct {
+	Name           string `json:"name"`
+	CPUDescription string `json:"cpu"`
+	RAMDescription string `json:"ram"`
+}
+
+// DiskType names the capacity SKU of a persistent disk type.
+type DiskType struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+// Accelerator names the SKUs of a GPU type, on-demand and attached to Spot
+// VMs.
+type Accelerator struct {
+	Type            string `json:"type"`
+	Description     string `json:"description"`
+	SpotDescription string `json:"spot_description"`
+}
+
+var data struct {
+	SpotPrefix   string        `json:"spot_prefix"`
+	Families     []Family      `json:"families"`
+	Disks        []DiskType    `json:"disks"`
+	Accelerators []Accelerator `json:"gpus"`
+	LocalSSD     struct {
+		Description     string `json:"description"`
+		SpotDescription string `json:"spot_description"`
+	} `json:"local_ssd"`
+}
+
+func init() {
+	if err := json.Unmarshal(familiesJSON, &data); err != nil {
+		panic(fmt.Sprintf("compute: invalid families.json: %v", err))
+	}
+}
+
+// FamilyByName returns the machine family called name, such as "n2".
+func FamilyByName(name string) (Family, bool) {
+	for _, f := range data.Families {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Family{}, false
+}
+
+// FamilyNames returns the names of the known machine families, sorted.
+func FamilyNames() []string {
+	names := make([]string, len(data.Families))
+	for i, f := range data.Families {
+		names[i] = f.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DiskTypeByName returns the disk type called name, such as "pd-ssd".
+func DiskTypeByName(name string) (DiskType, bool) {
+	for _, d := range data.Disks {
+		if d.Type == name {
+			return d, true
+		}
+	}
+	return DiskType{}, false
+}
+
+// DiskTypeNames returns the names of the known disk types.
+func DiskTypeNames() []string {
+	names := make([]string, len(data.Disks))
+	for i, d := range data.Disks {
+		names[i] = d.Type
+	}
+	return names
+}
+
+// AcceleratorByType returns the GPU type called name, such as
+// "nvidia-tesla-a100".
+func AcceleratorByType(name string) (Accelerator, bool) {
+	for _, a := range data.Accelerators {
+		if a.Type == name {
+			return a, true
+		}
+	}
+	return Accelerator{}, false
+}
@@ -0,0 +1,36 @@
+package compute
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFamilies(t *testing.T) {
+	f, ok := FamilyByName("n2")
+	if !ok || f.CPUDescription != "N2 Instance Core" || f.RAMDescription != "N2 Instance Ram" {
+		t.Fatalf("FamilyByName(n2) = %+v, %v", f, ok)
+	}
+	if _, ok := FamilyByName("z9"); ok {
+		t.Fatal("unknown family found")
+	}
+	if names := FamilyNames(); !slices.IsSorted(names) || !slices.Contains(names, "e2") {
+		t.Fatalf("FamilyNames = %v", names)
+	}
+	d, ok := DiskTypeByName("pd-balanced")
+	if !ok || d.Description != "Balanced PD Capacity" {
+		t.Fatalf("DiskTypeByName(pd-balanced) = %+v, %v", d, ok)
+	}
+	if names := DiskTypeNames(); len(names) != 4 || names[0] != "pd-standard" {
+		t.Fatalf("DiskTypeNames = %v", names)
+	}
+}
+
+func TestAccelerators(t *testing.T) {
+	a, ok := AcceleratorByType("nvidia-l4")
+	if !ok || a.Description != "Nvidia L4 GPU" || a.SpotDescription == "" {
+		t.Fatalf("AcceleratorByType(nvidia-l4) = %+v, %v", a, ok)
+	}
+	if _, ok := AcceleratorByType("tpu"); ok {
+		t.Fatal("unknown accelerator found")
+	}
+}
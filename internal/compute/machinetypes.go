@@ -0,0 +1,84 @@
+package compute
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"mcp-server/internal/database"
+)
+
+// machine_types.json lists the predefined machine types. Bump its version
+// whenever types are added, changed or removed, so Refresh drops the rows of
+// earlier versions.
+//
+//go:embed machine_types.json
+var machineTypesJSON []byte
+
+type machineTypeEntry struct {
+	Name   string `json:"name"`
+	Family string `json:"family"`
+	VCPUs  int    `json:"vcpus"`
+	// BilledVCPUs is set for shared-core types, which are billed for a
+	// fraction of their vCPUs.
+	BilledVCPUs float64 `json:"billed_vcpus"`
+	MemoryGB    float64 `json:"memory_gb"`
+	GPUs        int     `json:"gpus"`
+	GPUType     string  `json:"gpu_type"`
+	LocalSSDGB  float64 `json:"local_ssd_gb"`
+}
+
+// MachineTypes returns the version and machine types of the embedded
+// dataset.
+func MachineTypes() (string, []database.MachineType) {
+	var dataset struct {
+		Version      string             `json:"version"`
+		MachineTypes []machineTypeEntry `json:"machine_types"`
+	}
+	if err := json.Unmarshal(machineTypesJSON, &dataset); err != nil {
+		panic(fmt.Sprintf("compute: invalid machine_types.json: %v", err))
+	}
+	out := make([]database.MachineType, len(dataset.MachineTypes))
+	for i, e := range dataset.MachineTypes {
+		billed := e.BilledVCPUs
+		if billed == 0 {
+			billed = float64(e.VCPUs)
+		}
+		out[i] = database.MachineType{
+			Name:        e.Name,
+			Family:      e.Family,
+			VCPUs:       e.VCPUs,
+			BilledVCPUs: billed,
+			MemoryGB:    e.MemoryGB,
+			GPUs:        e.GPUs,
+			GPUType:     e.GPUType,
+			LocalSSDGB:  e.LocalSSDGB,
+			Version:     dataset.Version,
+		}
+	}
+	return dataset.Version, out
+}
+
+// MachineTypeStore writes the machine_types table.
+type MachineTypeStore interface {
+	UpsertMachineType(ctx context.Context, m database.MachineType) error
+	DeleteStaleMachineTypes(ctx context.Context, version string) (int64, error)
+}
+
+var _ MachineTypeStore = (*database.SQLRepository)(nil)
+
+// RefreshMachineTypes writes the embedded dataset to the machine_types table
+// and deletes the types of other dataset versions.
+func RefreshMachineTypes(ctx context.Context, s MachineTypeStore) error {
+	version, types := MachineTypes()
+	for _, m := range types {
+		if err := s.UpsertMachineType(ctx, m); err != nil {
+			return fmt.Errorf("write machine type %s: %w", m.Name, err)
+		}
+	}
+	if _, err := s.DeleteStaleMachineTypes(ctx, version); err != nil {
+		return fmt.Errorf("delete stale machine types: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package compute
+
+import (
+	"context"
+	"testing"
+
+	"mcp-server/internal/database"
+)
+
+func TestMachineTypes(t *testing.T) {
+	version, types := MachineTypes()
+	if version == "" {
+		t.Fatal("dataset has no version")
+	}
+	seen := map[string]database.MachineType{}
+	for _, m := range types {
+		if m.VCPUs <= 0 || m.BilledVCPUs <= 0 || m.BilledVCPUs > float64(m.VCPUs) || m.MemoryGB <= 0 || m.Version != version {
+			t.Fatalf("incomplete machine type %+v", m)
+		}
+		if _, ok := seen[m.Name]; ok {
+			t.Fatalf("duplicate machine type %s", m.Name)
+		}
+		seen[m.Name] = m
+		if _, ok := FamilyByName(m.Family); !ok {
+			t.Errorf("%s has unknown family %s", m.Name, m.Family)
+		}
+		if _, ok := AcceleratorByType(m.GPUType); m.GPUs > 0 && !ok {
+			t.Errorf("%s has unknown GPU type %q", m.Name, m.GPUType)
+		}
+	}
+	if m := seen["n2-standard-8"]; m.VCPUs != 8 || m.BilledVCPUs != 8 || m.MemoryGB != 32 {
+		t.Fatalf("n2-standard-8 = %+v", m)
+	}
+	// Shared-core types are billed for a fraction of their two vCPUs.
+	for name, billed := range map[string]float64{"e2-micro": 0.25, "e2-small": 0.5, "e2-medium": 1} {
+		if m := seen[name]; m.VCPUs != 2 || m.BilledVCPUs != billed {
+			t.Errorf("%s = %+v, want %v billed vCPUs", name, m, billed)
+		}
+	}
+}
+
+type fakeMachineTypeStore struct {
+	written map[string]database.MachineType
+	current string
+}
+
+func (f *fakeMachineTypeStore) UpsertMachineType(ctx context.Context, m database.MachineType) error {
+	f.written[m.Name] = m
+	return nil
+}
+
+func (f *fakeMachineTypeStore) DeleteStaleMachineTypes(ctx context.Context, version string) (int64, error) {
+	f.current = version
+	return 0, nil
+}
+
+func TestRefreshMachineTypes(t *testing.T) {
+	s := &fakeMachineTypeStore{written: map[string]database.MachineType{}}
+	if err := RefreshMachineTypes(context.Background(), s); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	version, types := MachineTypes()
+	if len(s.written) != len(types) || s.current != version {
+		t.Fatalf("wrote %d of %d types, kept version %q", len(s.written), len(types), s.current)
+	}
+}
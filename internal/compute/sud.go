@@ -0,0 +1,59 @@
+package compute
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"mcp-server/internal/database"
+	"mcp-server/internal/pricing"
+)
+
+// sud.json holds the sustained use discount tiers of the machine families
+// that earn them, and the SKU category they apply to.
+//
+//go:embed sud.json
+var sudJSON []byte
+
+var sud struct {
+	ResourceFamily string `json:"resource_family"`
+	UsageType      string `json:"usage_type"`
+	Rules          []struct {
+		Families []string          `json:"families"`
+		Tiers    []pricing.SUDTier `json:"tiers"`
+	} `json:"rules"`
+}
+
+func init() {
+	if err := json.Unmarshal(sudJSON, &sud); err != nil {
+		panic(fmt.Sprintf("compute: invalid sud.json: %v", err))
+	}
+}
+
+// SustainedUseTiers returns the sustained use discount tiers sku earns and
+// the machine family they come from. Only the on-demand vCPU and memory SKUs
+// of families with a rule are eligible.
+func SustainedUseTiers(sku database.SKU) (string, []pricing.SUDTier, bool) {
+	if sku.Category.ResourceFamily != sud.ResourceFamily || sku.Category.UsageType != sud.UsageType {
+		return "", nil, false
+	}
+	for _, r := range sud.Rules {
+		for _, name := range r.Families {
+			f, ok := FamilyByName(name)
+			if !ok {
+				continue
+			}
+			if isFamilySKU(sku.Description, f.CPUDescription) || isFamilySKU(sku.Description, f.RAMDescription) {
+				return name, r.Tiers, true
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// isFamilySKU reports whether description is base, optionally followed by
+// the location the SKU runs in.
+func isFamilySKU(description, base string) bool {
+	return description == base || strings.HasPrefix(description, base+" running in ")
+}
@@ -0,0 +1,27 @@
+package compute
+
+import (
+	"testing"
+
+	"mcp-server/internal/database"
+)
+
+func TestSustainedUseTiers(t *testing.T) {
+	onDemand := database.Category{ResourceFamily: "Compute", ResourceGroup: "N1Standard", UsageType: "OnDemand"}
+	tests := []struct {
+		sku    database.SKU
+		family string
+	}{
+		{database.SKU{Description: "N1 Predefined Instance Core running in Americas", Category: onDemand}, "n1"},
+		{database.SKU{Description: "N2D AMD Instance Ram running in EMEA", Category: onDemand}, "n2d"},
+		{database.SKU{Description: "E2 Instance Core running in Americas", Category: onDemand}, ""},
+		{database.SKU{Description: "N2 Instance Core running in Americas", Category: database.Category{ResourceFamily: "Compute", UsageType: "Preemptible"}}, ""},
+		{database.SKU{Description: "N2 Instance Core running in Americas", Category: database.Category{ResourceFamily: "Compute", UsageType: "Commit1Yr"}}, ""},
+	}
+	for _, tt := range tests {
+		family, tiers, ok := SustainedUseTiers(tt.sku)
+		if family != tt.family || ok != (tt.family != "") || ok && len(tiers) == 0 {
+			t.Errorf("SustainedUseTiers(%q, %s) = %q, %v, %v", tt.sku.Description, tt.sku.Category.UsageType, family, tiers, ok)
+		}
+	}
+}
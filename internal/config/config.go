@@ -0,0 +1,120 @@
+// Package config loads the settings shared by the server, the sync job and
+// the command-line tools. Every setting has a default and can be overridden,
+// in increasing order of precedence, by a YAML or TOML file, an environment
+// variable and a command-line flag.
+package config
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Config holds the settings of all binaries. Each leaf field is tagged with
+// its file key, environment variable and flag name; secret fields are
+// redacted when the configuration is printed.
+type Config struct {
+	Database  Database  `key:"database"`
+	Server    Server    `key:"server"`
+	GitHub    GitHub    `key:"github"`
+	Limits    Limits    `key:"limits"`
+	Sync      Sync      `key:"sync"`
+	Telemetry Telemetry `key:"telemetry"`
+}
+
+// Database configures the Turso/libSQL connection.
+type Database struct {
+	URL                 string        `key:"url" env:"DATABASE_URL" flag:"database-url" secret:"url" usage:"libSQL URL such as libsql://db.turso.io or file:cloud-pricing.db"`
+	AuthToken           string        `key:"auth_token" env:"TURSO_AUTH_TOKEN" flag:"database-auth-token" secret:"true" usage:"Turso auth token, if not part of the URL"`
+	ReadOnlyAuthToken   string        `key:"read_only_auth_token" env:"TURSO_READ_ONLY_AUTH_TOKEN" flag:"database-read-only-auth-token" secret:"true" usage:"read-only Turso token the server reads the catalog with"`
+	ReplicaPath         string        `key:"replica_path" env:"TURSO_REPLICA_PATH" flag:"database-replica-path" usage:"local embedded replica file; empty connects to the database directly"`
+	ReplicaSyncInterval time.Duration `key:"replica_sync_interval" env:"TURSO_REPLICA_SYNC_INTERVAL" flag:"database-replica-sync-interval" usage:"how often the embedded replica pulls from the primary"`
+}
+
+// Server configures the MCP server.
+type Server struct {
+	Port              int           `key:"port" env:"PORT" flag:"port" usage:"HTTP listen port"`
+	MaxSyncAge        time.Duration `key:"max_sync_age" env:"MAX_SYNC_AGE" flag:"max-sync-age" usage:"readiness fails when the last successful sync is older"`
+	ShutdownTimeout   time.Duration `key:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" flag:"shutdown-timeout" usage:"time allowed for tool calls to finish after SIGTERM"`
+	AuditRedactFields []string      `key:"audit_redact_fields" env:"AUDIT_REDACT_FIELDS" flag:"audit-redact-fields" usage:"comma-separated tool argument names redacted in the audit log"`
+	CacheSize         int           `key:"cache_size" env:"CACHE_SIZE" flag:"cache-size" usage:"SKU and pricing lookups kept in memory, 0 disables the cache"`
+	CacheTTL          time.Duration `key:"cache_ttl" env:"CACHE_TTL" flag:"cache-ttl" usage:"how long a cached lookup is served"`
+}
+
+// GitHub configures authentication against GitHub.
+type GitHub struct {
+	APIURL           string        `key:"api_url" env:"GITHUB_API_URL" flag:"github-api-url" usage:"GitHub REST API base URL"`
+	AllowedOrgs      []string      `key:"allowed_orgs" env:"GITHUB_ALLOWED_ORGS" flag:"github-allowed-orgs" usage:"comma-separated organizations whose members may sign in"`
+	AllowedTeams     []string      `key:"allowed_teams" env:"GITHUB_ALLOWED_TEAMS" flag:"github-allowed-teams" usage:"comma-separated org/team-slug teams whose members may sign in"`
+	RecheckInterval  time.Duration `key:"recheck_interval" env:"GITHUB_RECHECK_INTERVAL" flag:"github-recheck-interval" usage:"how long organization and team membership is cached"`
+	RoleBindingsFile string        `key:"role_bindings_file" env:"ROLE_BINDINGS_FILE" flag:"role-bindings-file" usage:"JSON file granting roles to users, orgs, teams and API keys"`
+}
+
+// Limits configures per-principal rate limits. A zero rate disables a limit,
+// and all limits are off by default.
+type Limits struct {
+	PrincipalRate  float64 `key:"principal_rate" env:"RATE_LIMIT_PRINCIPAL_RPS" flag:"rate-limit-principal-rps" usage:"sustained HTTP requests per second per principal"`
+	PrincipalBurst int     `key:"principal_burst" env:"RATE_LIMIT_PRINCIPAL_BURST" flag:"rate-limit-principal-burst" usage:"HTTP request burst per principal"`
+	ToolRate       float64 `key:"tool_rate" env:"RATE_LIMIT_TOOL_RPS" flag:"rate-limit-tool-rps" usage:"sustained calls per second per principal and tool"`
+	ToolBurst      int     `key:"tool_burst" env:"RATE_LIMIT_TOOL_BURST" flag:"rate-limit-tool-burst" usage:"call burst per principal and tool"`
+	DailyQuota     int     `key:"daily_quota" env:"DAILY_TOOL_QUOTA" flag:"daily-tool-quota" usage:"tool calls per principal per UTC day, 0 for unlimited"`
+}
+
+// Sync configures the pricing sync job.
+type Sync struct {
+	Concurrency int    `key:"concurrency" env:"SYNC_CONCURRENCY" flag:"sync-concurrency" usage:"services synced in parallel"`
+	Currency    string `key:"currency" env:"SYNC_CURRENCY" flag:"sync-currency" usage:"ISO 4217 currency prices are fetched in"`
+}
+
+// Telemetry configures logs, metrics and traces.
+type Telemetry struct {
+	LogLevel       string `key:"log_level" env:"LOG_LEVEL" flag:"log-level" usage:"debug, info, warn or error"`
+	OTLPEndpoint   string `key:"otlp_endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT" flag:"otlp-endpoint" usage:"OTLP/HTTP collector URL; empty disables tracing"`
+	PushgatewayURL string `key:"pushgateway_url" env:"METRICS_PUSHGATEWAY_URL" flag:"metrics-pushgateway-url" usage:"Pushgateway the sync job pushes its metrics to"`
+	MetricsFile    string `key:"metrics_textfile" env:"METRICS_TEXTFILE" flag:"metrics-textfile" usage:"file the sync job writes its metrics to for the node exporter"`
+}
+
+// Default returns the configuration used when nothing is overridden.
+func Default() Config {
+	return Config{
+		Database: Database{URL: "file:cloud-pricing.db", ReplicaSyncInterval: time.Minute},
+		Server: Server{
+			Port:            8080,
+			MaxSyncAge:      48 * time.Hour,
+			ShutdownTimeout: 7 * time.Second,
+			CacheSize:       10000,
+			CacheTTL:        10 * time.Minute,
+		},
+		GitHub: GitHub{
+			APIURL:          "https://api.github.com",
+			RecheckInterval: 15 * time.Minute,
+		},
+		Sync:      Sync{Concurrency: 1, Currency: "USD"},
+		Telemetry: Telemetry{LogLevel: "info"},
+	}
+}
+
+// DSN returns the database URL with the auth token, if any, appended.
+func (d Database) DSN() string {
+	return withToken(d.URL, d.AuthToken)
+}
+
+// ReadOnlyDSN returns the database URL with the read-only auth token
+// appended, falling back to the read-write token when none is set.
+func (d Database) ReadOnlyDSN() string {
+	if d.ReadOnlyAuthToken == "" {
+		return d.DSN()
+	}
+	return withToken(d.URL, d.ReadOnlyAuthToken)
+}
+
+func withToken(u, token string) string {
+	if token == "" {
+		return u
+	}
+	sep := "?"
+	if strings.Contains(u, "?") {
+		sep = "&"
+	}
+	return u + sep + "authToken=" + url.QueryEscape(token)
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func env(m map[string]string) func(string) (string, bool) {
+	return func(k string) (string, bool) {
+		v, ok := m[k]
+		return v, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	c, err := load("test", nil, env(nil), io.Discard, io.Discard)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if c.Server.Port != 8080 || c.Database.URL != "file:cloud-pricing.db" || c.Sync.Currency != "USD" || c.Sync.Concurrency != 1 {
+		t.Fatalf("defaults = %+v", c)
+	}
+	if c.Limits != (Limits{}) {
+		t.Fatalf("rate limits on by default: %+v", c.Limits)
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	yamlFile := writeFile(t, "c.yaml", `
+server:
+  port: 9000
+  max_sync_age: 12h
+github:
+  allowed_orgs: [acme, globex]
+sync:
+  concurrency: 4
+`)
+	c, err := load("test", []string{"-config", yamlFile, "-sync-concurrency", "8"},
+		env(map[string]string{"PORT": "9100"}), io.Discard, io.Discard)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if c.Server.Port != 9100 {
+		t.Errorf("port = %d, want env to override file", c.Server.Port)
+	}
+	if c.Server.MaxSyncAge != 12*time.Hour {
+		t.Errorf("max sync age = %s, want file to override default", c.Server.MaxSyncAge)
+	}
+	if c.Sync.Concurrency != 8 {
+		t.Errorf("concurrency = %d, want flag to override file", c.Sync.Concurrency)
+	}
+	if strings.Join(c.GitHub.AllowedOrgs, ",") != "acme,globex" {
+		t.Errorf("orgs = %v", c.GitHub.AllowedOrgs)
+	}
+}
+
+func TestLoad_TOML(t *testing.T) {
+	path := writeFile(t, "c.toml", `
+[database]
+url = "libsql://db.turso.io"
+
+[limits]
+daily_quota = 500
+`)
+	c, err := load("test", nil, env(map[string]string{FileEnv: path}), io.Discard, io.Discard)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if c.Database.URL != "libsql://db.turso.io" || c.Limits.DailyQuota != 500 {
+		t.Fatalf("config = %+v", c)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	path := writeFile(t, "c.yaml", "server:\n  prot: 80\n")
+	_, err := load("test", []string{"-config", path}, env(nil), io.Discard, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), `unknown setting "server.prot"`) {
+		t.Fatalf("unknown key: got %v", err)
+	}
+
+	_, err = load("test", nil, env(map[string]string{"PORT": "70000", "SYNC_CURRENCY": "usd"}), io.Discard, io.Discard)
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{"server.port: 70000 is not between 1 and 65535; set PORT, -port or server.port", `sync.currency: "usd"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+
+	_, err = load("test", nil, env(map[string]string{"TURSO_REPLICA_PATH": "/tmp/replica.db"}), io.Discard, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "database.url: must be a libsql:// or https:// primary") {
+		t.Fatalf("replica of a local file: got %v", err)
+	}
+
+	_, err = load("test", nil, env(map[string]string{"MAX_SYNC_AGE": "two days"}), io.Discard, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "MAX_SYNC_AGE: invalid duration") {
+		t.Fatalf("bad duration: got %v", err)
+	}
+}
+
+func TestLoad_PrintConfigRedactsSecrets(t *testing.T) {
+	var out bytes.Buffer
+	_, err := load("test", []string{"-print-config"}, env(map[string]string{
+		"DATABASE_URL":     "libsql://db.turso.io?authToken=secret1",
+		"TURSO_AUTH_TOKEN": "secret2",
+	}), &out, io.Discard)
+	if !errors.Is(err, ErrPrinted) {
+		t.Fatalf("load: %v", err)
+	}
+	s := out.String()
+	if strings.Contains(s, "secret1") || strings.Contains(s, "secret2") {
+		t.Fatalf("secrets printed:\n%s", s)
+	}
+	if !strings.Contains(s, "database.url: libsql://db.turso.io?authToken=REDACTED") || !strings.Contains(s, "database.auth_token: [REDACTED]") {
+		t.Fatalf("unexpected output:\n%s", s)
+	}
+}
+
+func TestDatabase_DSN(t *testing.T) {
+	d := Database{URL: "libsql://db.turso.io", AuthToken: "t"}
+	if got := d.DSN(); got != "libsql://db.turso.io?authToken=t" {
+		t.Fatalf("DSN = %q", got)
+	}
+}
+
+func TestDatabase_ReadOnlyDSN(t *testing.T) {
+	d := Database{URL: "libsql://db.turso.io", AuthToken: "rw"}
+	if got := d.ReadOnlyDSN(); got != "libsql://db.turso.io?authToken=rw" {
+		t.Fatalf("without a read-only token ReadOnlyDSN = %q", got)
+	}
+	d.ReadOnlyAuthToken = "ro"
+	if got := d.ReadOnlyDSN(); got != "libsql://db.turso.io?authToken=ro" {
+		t.Fatalf("ReadOnlyDSN = %q", got)
+	}
+}
@@ -0,0 +1,293 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the environment variable holding the configuration file path.
+const FileEnv = "CONFIG_FILE"
+
+// redacted replaces secret values when the configuration is printed.
+const redacted = "[REDACTED]"
+
+// field describes one leaf setting of Config.
+type field struct {
+	key    string // file key such as "database.url"
+	env    string
+	flag   string
+	usage  string
+	secret string // "", "true" or "url"
+	value  reflect.Value
+}
+
+// source tells the user every way of setting f.
+func (f field) source() string {
+	return fmt.Sprintf("set %s, -%s or %s in the config file", f.env, f.flag, f.key)
+}
+
+// fields lists the leaf settings of c in declaration order.
+func fields(c *Config) []field {
+	var out []field
+	var walk func(v reflect.Value, prefix string)
+	walk = func(v reflect.Value, prefix string) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			key := prefix + sf.Tag.Get("key")
+			if sf.Type.Kind() == reflect.Struct {
+				walk(v.Field(i), key+".")
+				continue
+			}
+			out = append(out, field{
+				key:    key,
+				env:    sf.Tag.Get("env"),
+				flag:   sf.Tag.Get("flag"),
+				usage:  sf.Tag.Get("usage"),
+				secret: sf.Tag.Get("secret"),
+				value:  v.Field(i),
+			})
+		}
+	}
+	walk(reflect.ValueOf(c).Elem(), "")
+	return out
+}
+
+// Load builds the configuration of the binary called name from its defaults,
+// the file named by -config or CONFIG_FILE, the environment and args. It
+// returns flag.ErrHelp after printing usage for -h. When -print-config is
+// given it prints the redacted configuration to stdout and returns
+// ErrPrinted.
+func Load(name string, args []string) (Config, error) {
+	return load(name, args, os.LookupEnv, os.Stdout, os.Stderr)
+}
+
+// ErrPrinted is returned by Load after printing the configuration.
+var ErrPrinted = errors.New("configuration printed")
+
+// MustLoad calls Load and exits the process when it fails, printing the
+// problems found, or when the configuration was printed or help requested.
+func MustLoad(name string, args []string) Config {
+	c, err := Load(name, args)
+	switch {
+	case errors.Is(err, ErrPrinted):
+		os.Exit(0)
+	case errors.Is(err, flag.ErrHelp):
+		os.Exit(2)
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "%s: invalid configuration:\n%v\n", name, err)
+		os.Exit(2)
+	}
+	return c
+}
+
+func load(name string, args []string, lookupEnv func(string) (string, bool), stdout, stderr io.Writer) (Config, error) {
+	c := Default()
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("config", "", "YAML or TOML configuration file (env "+FileEnv+")")
+	printConfig := fs.Bool("print-config", false, "print the configuration with secrets redacted and exit")
+	flagged := map[string]string{}
+	for _, f := range fields(&c) {
+		fs.Func(f.flag, fmt.Sprintf("%s (env %s)", f.usage, f.env), func(s string) error {
+			flagged[f.flag] = s
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+	if fs.NArg() > 0 {
+		return Config{}, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	if *path == "" {
+		*path, _ = lookupEnv(FileEnv)
+	}
+
+	var errs []error
+	if *path != "" {
+		values, err := readFile(*path)
+		if err != nil {
+			return Config{}, err
+		}
+		for _, f := range fields(&c) {
+			v, ok := values[f.key]
+			if !ok {
+				continue
+			}
+			delete(values, f.key)
+			if err := set(f.value, fileString(v)); err != nil {
+				errs = append(errs, fmt.Errorf("%s in %s: %w", f.key, *path, err))
+			}
+		}
+		for key := range values {
+			errs = append(errs, fmt.Errorf("%s: unknown setting %q", *path, key))
+		}
+	}
+	for _, f := range fields(&c) {
+		if s, ok := lookupEnv(f.env); ok && s != "" {
+			if err := set(f.value, s); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", f.env, err))
+			}
+		}
+	}
+	for _, f := range fields(&c) {
+		if s, ok := flagged[f.flag]; ok {
+			if err := set(f.value, s); err != nil {
+				errs = append(errs, fmt.Errorf("-%s: %w", f.flag, err))
+			}
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return Config{}, err
+	}
+	if err := c.Validate(); err != nil {
+		return Config{}, err
+	}
+	if *printConfig {
+		fmt.Fprint(stdout, c.Redacted())
+		return c, ErrPrinted
+	}
+	return c, nil
+}
+
+// readFile parses a YAML or TOML file, chosen by extension, into settings
+// keyed like "section.key".
+func readFile(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	doc := map[string]any{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &doc)
+	case ".toml":
+		err = toml.Unmarshal(data, &doc)
+	default:
+		return nil, fmt.Errorf("config file %s: unsupported extension %q, want .yaml, .yml or .toml", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	values := map[string]any{}
+	for section, v := range doc {
+		m, ok := v.(map[string]any)
+		if !ok {
+			values[section] = v
+			continue
+		}
+		for key, v := range m {
+			values[section+"."+key] = v
+		}
+	}
+	return values, nil
+}
+
+// fileString converts a decoded file value to the string form used by
+// environment variables and flags.
+func fileString(v any) string {
+	if list, ok := v.([]any); ok {
+		parts := make([]string, len(list))
+		for i, e := range list {
+			parts[i] = fmt.Sprint(e)
+		}
+		return strings.Join(parts, ",")
+	}
+	return fmt.Sprint(v)
+}
+
+// set parses s into v according to its type.
+func set(v reflect.Value, s string) error {
+	s = strings.TrimSpace(s)
+	switch v.Interface().(type) {
+	case time.Duration:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q, want a value such as 30s or 15m", s)
+		}
+		v.SetInt(int64(d))
+	case string:
+		v.SetString(s)
+	case int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		v.SetInt(int64(n))
+	case float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		v.SetFloat(f)
+	case []string:
+		var list []string
+		for _, e := range strings.Split(s, ",") {
+			if e = strings.TrimSpace(e); e != "" {
+				list = append(list, e)
+			}
+		}
+		v.Set(reflect.ValueOf(list))
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+	return nil
+}
+
+// Redacted renders the configuration one "key: value" line per setting,
+// replacing secrets and the credentials embedded in URLs.
+func (c Config) Redacted() string {
+	var b strings.Builder
+	for _, f := range fields(&c) {
+		s := display(f.value)
+		switch {
+		case s == "":
+		case f.secret == "url":
+			s = redactURL(s)
+		case f.secret != "":
+			s = redacted
+		}
+		fmt.Fprintf(&b, "%s: %s\n", f.key, s)
+	}
+	return b.String()
+}
+
+func display(v reflect.Value) string {
+	if list, ok := v.Interface().([]string); ok {
+		return strings.Join(list, ",")
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// redactURL hides the password and query parameter values of a URL, where
+// libSQL URLs carry auth tokens.
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		return redacted
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "REDACTED")
+	}
+	if u.RawQuery != "" {
+		q := u.Query()
+		for k := range q {
+			q.Set(k, "REDACTED")
+		}
+		u.RawQuery = q.Encode()
+	}
+	return u.String()
+}
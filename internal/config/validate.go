@@ -0,0 +1,102 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Validate checks every setting and reports all problems at once, each
+// naming the ways to fix it.
+func (c Config) Validate() error {
+	byKey := map[string]field{}
+	for _, f := range fields(&c) {
+		byKey[f.key] = f
+	}
+	var errs []error
+	invalid := func(key, format string, args ...any) {
+		f := byKey[key]
+		errs = append(errs, fmt.Errorf("%s: %s; %s", key, fmt.Sprintf(format, args...), f.source()))
+	}
+
+	if c.Database.URL == "" {
+		invalid("database.url", "is required")
+	}
+	if c.Database.ReplicaPath != "" {
+		if u, err := url.Parse(c.Database.URL); err != nil || (u.Scheme != "libsql" && u.Scheme != "https" && u.Scheme != "http") {
+			invalid("database.url", "must be a libsql:// or https:// primary when database.replica_path is set")
+		}
+		if c.Database.ReplicaSyncInterval <= 0 {
+			invalid("database.replica_sync_interval", "must be positive")
+		}
+	}
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		invalid("server.port", "%d is not between 1 and 65535", c.Server.Port)
+	}
+	if c.Server.MaxSyncAge < 0 {
+		invalid("server.max_sync_age", "must not be negative, use 0 to disable the check")
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		invalid("server.shutdown_timeout", "must be positive")
+	}
+	if c.Server.CacheSize < 0 {
+		invalid("server.cache_size", "must not be negative, use 0 to disable the cache")
+	}
+	if c.Server.CacheTTL <= 0 {
+		invalid("server.cache_ttl", "must be positive")
+	}
+	if u, err := url.Parse(c.GitHub.APIURL); err != nil || u.Scheme == "" || u.Host == "" {
+		invalid("github.api_url", "%q is not an absolute URL", c.GitHub.APIURL)
+	}
+	for _, t := range c.GitHub.AllowedTeams {
+		if org, slug, ok := strings.Cut(t, "/"); !ok || org == "" || slug == "" {
+			invalid("github.allowed_teams", "%q is not of the form org/team-slug", t)
+		}
+	}
+	if c.GitHub.RecheckInterval <= 0 {
+		invalid("github.recheck_interval", "must be positive")
+	}
+	if p := c.GitHub.RoleBindingsFile; p != "" {
+		if _, err := os.Stat(p); err != nil {
+			invalid("github.role_bindings_file", "%v", err)
+		}
+	}
+	if c.Limits.PrincipalRate < 0 || c.Limits.ToolRate < 0 || c.Limits.PrincipalBurst < 0 || c.Limits.ToolBurst < 0 {
+		invalid("limits.principal_rate", "rates and bursts must not be negative, use 0 to disable a limit")
+	}
+	if c.Limits.PrincipalRate > 0 && c.Limits.PrincipalBurst == 0 {
+		invalid("limits.principal_burst", "must be positive when limits.principal_rate is set")
+	}
+	if c.Limits.ToolRate > 0 && c.Limits.ToolBurst == 0 {
+		invalid("limits.tool_burst", "must be positive when limits.tool_rate is set")
+	}
+	if c.Limits.DailyQuota < 0 {
+		invalid("limits.daily_quota", "must not be negative, use 0 for unlimited")
+	}
+	if c.Sync.Concurrency < 1 || c.Sync.Concurrency > 64 {
+		invalid("sync.concurrency", "%d is not between 1 and 64", c.Sync.Concurrency)
+	}
+	if !isCurrency(c.Sync.Currency) {
+		invalid("sync.currency", "%q is not a three-letter ISO 4217 code such as USD", c.Sync.Currency)
+	}
+	switch strings.ToLower(c.Telemetry.LogLevel) {
+	case "debug", "info", "warn", "error":
+	default:
+		invalid("telemetry.log_level", "%q is not one of debug, info, warn or error", c.Telemetry.LogLevel)
+	}
+	return errors.Join(errs...)
+}
+
+func isCurrency(s string) bool {
+	if len(s) != 3 {
+		return false
+	}
+	for _, c := range s {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// InsertAPIKey stores a new API key.
+func (r *SQLRepository) InsertAPIKey(ctx context.Context, k APIKey) (err error) {
+	ctx, span := startSpan(ctx, "InsertAPIKey")
+	defer func() { endSpan(span, err) }()
+	_, err = r.db.ExecContext(ctx, `INSERT INTO api_keys (name, key_hash, created_at, expires_at)
+VALUES (?, ?, ?, ?)`, k.Name, k.KeyHash, k.CreatedAt, k.ExpiresAt)
+	return err
+}
+
+// APIKeyByHash returns the API key with the given hash.
+// It returns sql.ErrNoRows if no key matches.
+func (r *SQLRepository) APIKeyByHash(ctx context.Context, hash string) (_ APIKey, err error) {
+	ctx, span := startSpan(ctx, "APIKeyByHash")
+	defer func() { endSpan(span, err) }()
+	row := r.db.QueryRowContext(ctx, `SELECT key_id, name, key_hash, created_at, expires_at, last_used_at, revoked_at
+FROM api_keys WHERE key_hash = ?`, hash)
+	return scanAPIKey(row)
+}
+
+// ListAPIKeys returns all API keys ordered by name.
+func (r *SQLRepository) ListAPIKeys(ctx context.Context) (_ []APIKey, err error) {
+	ctx, span := startSpan(ctx, "ListAPIKeys")
+	defer func() { endSpan(span, err) }()
+	rows, err := r.db.QueryContext(ctx, `SELECT key_id, name, key_hash, created_at, expires_at, last_used_at, revoked_at
+FROM api_keys ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var keys []APIKey
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// RevokeAPIKey marks the named API key as revoked.
+func (r *SQLRepository) RevokeAPIKey(ctx context.Context, name string, at time.Time) (err error) {
+	ctx, span := startSpan(ctx, "RevokeAPIKey")
+	defer func() { endSpan(span, err) }()
+	res, err := r.db.ExecContext(ctx, `UPDATE api_keys SET revoked_at = ? WHERE name = ? AND revoked_at IS NULL`, at, name)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("api key %q not found or already revoked", name)
+	}
+	return nil
+}
+
+// TouchAPIKey records that the API key was used at the given time.
+func (r *SQLRepository) TouchAPIKey(ctx context.Context, keyID int64, at time.Time) (err error) {
+	ctx, span := startSpan(ctx, "TouchAPIKey")
+	defer func() { endSpan(span, err) }()
+	_, err = r.db.ExecContext(ctx, `UPDATE api_keys SET last_used_at = ? WHERE key_id = ?`, at, keyID)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAPIKey(row rowScanner) (APIKey, error) {
+	var k APIKey
+	var lastUsed, revoked sql.NullTime
+	if err := row.Scan(&k.KeyID, &k.Name, &k.KeyHash, &k.CreatedAt, &k.ExpiresAt, &lastUsed, &revoked); err != nil {
+		return APIKey{}, err
+	}
+	k.LastUsedAt = lastUsed.Time
+	k.RevokedAt = revoked.Time
+	return k, nil
+}
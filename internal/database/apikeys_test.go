@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSQLRepository_APIKeys(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	key := APIKey{Name: "ci", KeyHash: "abc", CreatedAt: created, ExpiresAt: created.Add(24 * time.Hour)}
+	if err := repo.InsertAPIKey(ctx, key); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if err := repo.InsertAPIKey(ctx, key); err == nil {
+		t.Fatal("expected error inserting duplicate name")
+	}
+
+	got, err := repo.APIKeyByHash(ctx, "abc")
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	if got.Name != "ci" || !got.ExpiresAt.Equal(key.ExpiresAt) || !got.LastUsedAt.IsZero() || !got.RevokedAt.IsZero() {
+		t.Fatalf("key mismatch: %+v", got)
+	}
+	if _, err := repo.APIKeyByHash(ctx, "missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("lookup missing: got %v, want sql.ErrNoRows", err)
+	}
+
+	used := created.Add(time.Hour)
+	if err := repo.TouchAPIKey(ctx, got.KeyID, used); err != nil {
+		t.Fatalf("touch: %v", err)
+	}
+	if err := repo.RevokeAPIKey(ctx, "ci", used); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if err := repo.RevokeAPIKey(ctx, "ci", used); err == nil {
+		t.Fatal("expected error revoking twice")
+	}
+
+	keys, err := repo.ListAPIKeys(ctx)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(keys) != 1 || !keys[0].LastUsedAt.Equal(used) || !keys[0].RevokedAt.Equal(used) {
+		t.Fatalf("list = %+v", keys)
+	}
+}
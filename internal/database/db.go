@@ -1,13 +1,16 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"embed"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
-	_ "github.com/tursodatabase/libsql-client-go/libsql"
 	_ "modernc.org/sqlite"
 )
 
@@ -28,20 +31,67 @@ func Connect(url string) (*sql.DB, error) {
 	return db, nil
 }
 
-// Migrate applies all embedded SQL migrations in lexical order.
-func Migrate(db *sql.DB) error {
-	entries, err := migrationFiles.ReadDir("migrations")
+// ConnectReadOnly opens a connection that cannot write. Local files get the
+// query_only pragma on every pooled connection; Turso enforces read-only
+// access through the auth token, so url should carry a read-only token.
+func ConnectReadOnly(url string) (*sql.DB, error) {
+	if !strings.HasPrefix(url, "file:") {
+		return Connect(url)
+	}
+	c, err := newConnector(url)
 	if err != nil {
-		return fmt.Errorf("read migrations: %w", err)
+		return nil, err
 	}
-	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
-	for _, e := range entries {
-		if e.IsDir() {
-			continue
+	db := sql.OpenDB(queryOnlyConnector{c})
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// queryOnlyConnector sets the query_only pragma, which makes SQLite reject
+// every write, on each new connection.
+type queryOnlyConnector struct {
+	driver.Connector
+}
+
+func (c queryOnlyConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	execer, ok := conn.(driver.ExecerContext)
+	if !ok {
+		conn.Close()
+		return nil, errors.New("sqlite connection cannot execute pragmas")
+	}
+	for _, pragma := range []string{"PRAGMA query_only = ON", "PRAGMA foreign_keys = ON"} {
+		if _, err := execer.ExecContext(ctx, pragma, nil); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("%s: %w", pragma, err)
 		}
-		content, err := migrationFiles.ReadFile("migrations/" + e.Name())
+	}
+	return conn, nil
+}
+
+// Migrate applies the embedded SQL migrations that have not been applied yet
+// in lexical order, recording each one in the schema_migrations table.
+func Migrate(db *sql.DB) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+    name TEXT PRIMARY KEY,
+    applied_at TIMESTAMP NOT NULL
+)`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	pending, err := PendingMigrations(context.Background(), db)
+	if err != nil {
+		return err
+	}
+	for _, name := range pending {
+		content, err := migrationFiles.ReadFile("migrations/" + name)
 		if err != nil {
-			return fmt.Errorf("read migration %s: %w", e.Name(), err)
+			return fmt.Errorf("read migration %s: %w", name, err)
 		}
 		statements := strings.Split(string(content), ";")
 		for _, stmt := range statements {
@@ -50,9 +100,45 @@ func Migrate(db *sql.DB) error {
 				continue
 			}
 			if _, err := db.Exec(stmt); err != nil {
-				return fmt.Errorf("exec migration %s: %w", e.Name(), err)
+				return fmt.Errorf("exec migration %s: %w", name, err)
 			}
 		}
+		if _, err := db.Exec("INSERT INTO schema_migrations (name, applied_at) VALUES (?, ?)", name, time.Now().UTC()); err != nil {
+			return fmt.Errorf("record migration %s: %w", name, err)
+		}
 	}
 	return nil
 }
+
+// PendingMigrations returns the names of embedded migrations that have not
+// been applied to db, in the order Migrate would apply them.
+func PendingMigrations(ctx context.Context, db *sql.DB) ([]string, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	rows, err := db.QueryContext(ctx, "SELECT name FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("read applied migrations: %w", err)
+	}
+	defer rows.Close()
+	applied := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		applied[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	var pending []string
+	for _, e := range entries {
+		if !e.IsDir() && !applied[e.Name()] {
+			pending = append(pending, e.Name())
+		}
+	}
+	return pending, nil
+}
@@ -1,8 +1,10 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"path/filepath"
 	"testing"
 )
 
@@ -17,7 +19,7 @@ func TestMigrateCreatesTables(t *testing.T) {
 		t.Fatalf("migrate: %v", err)
 	}
 
-	tables := []string{"services", "skus", "pricing_info", "pricing_updates"}
+	tables := []string{"services", "skus", "pricing_info", "pricing_updates", "api_keys", "tool_call_quotas", "tool_calls", "regions", "schema_migrations"}
 	for _, tbl := range tables {
 		var name string
 		err := db.QueryRow("SELECT name FROM sqlite_master WHERE type='table' AND name=?", tbl).Scan(&name)
@@ -81,3 +83,36 @@ func TestMigrateColumnTypes(t *testing.T) {
 		}
 	}
 }
+
+func TestConnectReadOnly(t *testing.T) {
+	url := "file:" + filepath.Join(t.TempDir(), "catalog.db")
+	rw, err := Connect(url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer rw.Close()
+	if err := Migrate(rw); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := NewRepository(rw).UpsertService(context.Background(), Service{ServiceID: "svc", DisplayName: "Svc", BusinessEntityName: "Ent"}); err != nil {
+		t.Fatalf("write through read-write handle: %v", err)
+	}
+
+	ro, err := ConnectReadOnly(url)
+	if err != nil {
+		t.Fatalf("connect read-only: %v", err)
+	}
+	defer ro.Close()
+	ro.SetMaxOpenConns(3)
+	repo := NewRepository(ro)
+	// Hold several connections at once so the pragma is checked on each.
+	for range 3 {
+		var n int
+		if err := ro.QueryRow("SELECT COUNT(*) FROM services").Scan(&n); err != nil || n != 1 {
+			t.Fatalf("read: %d services, %v", n, err)
+		}
+		if err := repo.UpsertService(context.Background(), Service{ServiceID: "other", DisplayName: "O", BusinessEntityName: "Ent"}); err == nil {
+			t.Fatal("write through read-only handle succeeded")
+		}
+	}
+}
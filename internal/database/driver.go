@@ -0,0 +1,22 @@
+//go:build !(libsql_replica && cgo)
+
+package database
+
+import (
+	"database/sql/driver"
+	"errors"
+
+	"github.com/tursodatabase/libsql-client-go/libsql"
+)
+
+// newConnector returns the libsql connector for url.
+func newConnector(url string) (driver.Connector, error) {
+	return libsql.NewConnector(url)
+}
+
+// openReplica reports that this binary cannot open embedded replicas: they
+// need the cgo libsql driver, which registers the same driver name as the
+// pure Go client used here.
+func openReplica(opts ReplicaOptions) (*Replica, error) {
+	return nil, errors.New("embedded replicas need a binary built with cgo and -tags libsql_replica")
+}
@@ -0,0 +1,51 @@
+//go:build libsql_replica && cgo
+
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+
+	"github.com/tursodatabase/go-libsql"
+)
+
+// newConnector returns the libsql connector for url.
+func newConnector(url string) (driver.Connector, error) {
+	db, err := sql.Open("libsql", url)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	dc, ok := db.Driver().(driver.DriverContext)
+	if !ok {
+		return nil, errors.New("libsql driver does not support connectors")
+	}
+	return dc.OpenConnector(url)
+}
+
+func openReplica(opts ReplicaOptions) (*Replica, error) {
+	var libsqlOpts []libsql.Option
+	if opts.AuthToken != "" {
+		libsqlOpts = append(libsqlOpts, libsql.WithAuthToken(opts.AuthToken))
+	}
+	if opts.SyncInterval > 0 {
+		libsqlOpts = append(libsqlOpts, libsql.WithSyncInterval(opts.SyncInterval))
+	}
+	c, err := libsql.NewEmbeddedReplicaConnector(opts.Path, opts.PrimaryURL, libsqlOpts...)
+	if err != nil {
+		return nil, err
+	}
+	var conn driver.Connector = c
+	if opts.ReadOnly {
+		conn = queryOnlyConnector{c}
+	}
+	return &Replica{DB: sql.OpenDB(conn), syncer: connectorSyncer{c}}, nil
+}
+
+type connectorSyncer struct{ c *libsql.Connector }
+
+func (s connectorSyncer) sync() (int, error) {
+	r, err := s.c.Sync()
+	return r.FramesSynced, err
+}
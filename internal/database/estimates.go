@@ -0,0 +1,185 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// DefaultEstimateDriftLimit caps the number of drift records returned when the
+// filter has no limit.
+const DefaultEstimateDriftLimit = 100
+
+// InsertEstimate stores a new estimate.
+func (r *SQLRepository) InsertEstimate(ctx context.Context, e Estimate) (err error) {
+	ctx, span := startSpan(ctx, "InsertEstimate")
+	defer func() { endSpan(span, err) }()
+	_, err = r.db.ExecContext(ctx, `INSERT INTO estimates (estimate_id, owner, name, input, currency, total, share_token_hash, created_at, priced_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, e.EstimateID, e.Owner, e.Name, e.Input, e.Currency, e.Total, nullString(e.ShareTokenHash), e.CreatedAt, e.PricedAt)
+	return err
+}
+
+// EstimateByID returns the estimate with the given ID, or sql.ErrNoRows.
+func (r *SQLRepository) EstimateByID(ctx context.Context, estimateID string) (_ Estimate, err error) {
+	ctx, span := startSpan(ctx, "EstimateByID")
+	defer func() { endSpan(span, err) }()
+	return scanEstimate(r.db.QueryRowContext(ctx, estimateQuery+` WHERE estimate_id = ?`, estimateID))
+}
+
+// EstimateByShareHash returns the estimate shared with the token of the
+// given hash, or sql.ErrNoRows.
+func (r *SQLRepository) EstimateByShareHash(ctx context.Context, hash string) (_ Estimate, err error) {
+	ctx, span := startSpan(ctx, "EstimateByShareHash")
+	defer func() { endSpan(span, err) }()
+	return scanEstimate(r.db.QueryRowContext(ctx, estimateQuery+` WHERE share_token_hash = ?`, hash))
+}
+
+// ListEstimates returns the estimates of owner, newest first. An empty owner
+// lists the estimates of everyone.
+func (r *SQLRepository) ListEstimates(ctx context.Context, owner string) (_ []Estimate, err error) {
+	ctx, span := startSpan(ctx, "ListEstimates")
+	defer func() { endSpan(span, err) }()
+	query, args := estimateQuery, []any{}
+	if owner != "" {
+		query += ` WHERE owner = ?`
+		args = append(args, owner)
+	}
+	rows, err := r.db.QueryContext(ctx, query+` ORDER BY created_at DESC, estimate_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var estimates []Estimate
+	for rows.Next() {
+		e, err := scanEstimate(rows)
+		if err != nil {
+			return nil, err
+		}
+		estimates = append(estimates, e)
+	}
+	return estimates, rows.Err()
+}
+
+// UpdateEstimatePricing records the total of an estimate priced at at. It
+// returns sql.ErrNoRows when the estimate does not exist.
+func (r *SQLRepository) UpdateEstimatePricing(ctx context.Context, estimateID, currency string, total float64, at time.Time) (err error) {
+	ctx, span := startSpan(ctx, "UpdateEstimatePricing")
+	defer func() { endSpan(span, err) }()
+	res, err := r.db.ExecContext(ctx, `UPDATE estimates SET currency = ?, total = ?, priced_at = ? WHERE estimate_id = ?`, currency, total, at, estimateID)
+	if err != nil {
+		return err
+	}
+	return requireRow(res)
+}
+
+// SetEstimateShareHash sets, or clears when hash is empty, the share token
+// hash of an estimate of owner. It returns sql.ErrNoRows when owner has no
+// such estimate.
+func (r *SQLRepository) SetEstimateShareHash(ctx context.Context, estimateID, owner, hash string) (err error) {
+	ctx, span := startSpan(ctx, "SetEstimateShareHash")
+	defer func() { endSpan(span, err) }()
+	res, err := r.db.ExecContext(ctx, `UPDATE estimates SET share_token_hash = ? WHERE estimate_id = ? AND owner = ?`, nullString(hash), estimateID, owner)
+	if err != nil {
+		return err
+	}
+	return requireRow(res)
+}
+
+// DeleteEstimate deletes an estimate of owner with its drift. It returns
+// sql.ErrNoRows when owner has no such estimate.
+func (r *SQLRepository) DeleteEstimate(ctx context.Context, estimateID, owner string) (err error) {
+	ctx, span := startSpan(ctx, "DeleteEstimate")
+	defer func() { endSpan(span, err) }()
+	res, err := r.db.ExecContext(ctx, `DELETE FROM estimates WHERE estimate_id = ? AND owner = ?`, estimateID, owner)
+	if err != nil {
+		return err
+	}
+	if err := requireRow(res); err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `DELETE FROM estimate_drift WHERE estimate_id = ?`, estimateID)
+	return err
+}
+
+// InsertEstimateDrift records a change of an estimate's total.
+func (r *SQLRepository) InsertEstimateDrift(ctx context.Context, d EstimateDrift) (err error) {
+	ctx, span := startSpan(ctx, "InsertEstimateDrift")
+	defer func() { endSpan(span, err) }()
+	_, err = r.db.ExecContext(ctx, `INSERT INTO estimate_drift (estimate_id, currency, previous_total, total, previous_priced_at, priced_at)
+VALUES (?, ?, ?, ?, ?, ?)`, d.EstimateID, d.Currency, d.PreviousTotal, d.Total, d.PreviousPricedAt, d.PricedAt)
+	return err
+}
+
+// ListEstimateDrift returns the drift of estimates that still exist, newest
+// first, with the name of each estimate.
+func (r *SQLRepository) ListEstimateDrift(ctx context.Context, f EstimateDriftFilter) (_ []EstimateDrift, err error) {
+	ctx, span := startSpan(ctx, "ListEstimateDrift")
+	defer func() { endSpan(span, err) }()
+	var where []string
+	var args []any
+	if f.Owner != "" {
+		where = append(where, "e.owner = ?")
+		args = append(args, f.Owner)
+	}
+	if f.EstimateID != "" {
+		where = append(where, "d.estimate_id = ?")
+		args = append(args, f.EstimateID)
+	}
+	if !f.Since.IsZero() {
+		where = append(where, "d.priced_at >= ?")
+		args = append(args, f.Since)
+	}
+	query := `SELECT d.drift_id, d.estimate_id, e.name, d.currency, d.previous_total, d.total, d.previous_priced_at, d.priced_at
+FROM estimate_drift d JOIN estimates e ON e.estimate_id = d.estimate_id`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	limit := f.Limit
+	if limit <= 0 {
+		limit = DefaultEstimateDriftLimit
+	}
+	query += " ORDER BY d.priced_at DESC, d.drift_id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var drift []EstimateDrift
+	for rows.Next() {
+		var d EstimateDrift
+		if err := rows.Scan(&d.DriftID, &d.EstimateID, &d.Name, &d.Currency, &d.PreviousTotal, &d.Total, &d.PreviousPricedAt, &d.PricedAt); err != nil {
+			return nil, err
+		}
+		drift = append(drift, d)
+	}
+	return drift, rows.Err()
+}
+
+// requireRow returns sql.ErrNoRows when a statement changed no row.
+func requireRow(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+const estimateQuery = `SELECT estimate_id, owner, name, input, currency, total, share_token_hash, created_at, priced_at FROM estimates`
+
+func scanEstimate(row rowScanner) (Estimate, error) {
+	var (
+		e     Estimate
+		share sql.NullString
+	)
+	if err := row.Scan(&e.EstimateID, &e.Owner, &e.Name, &e.Input, &e.Currency, &e.Total, &share, &e.CreatedAt, &e.PricedAt); err != nil {
+		return Estimate{}, err
+	}
+	e.ShareTokenHash = share.String
+	return e, nil
+}
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSQLRepository_Estimates(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	for i, e := range []Estimate{
+		{EstimateID: "a", Owner: "github:octocat", Name: "web", Input: []byte(`{"lines":[]}`), Currency: "USD", Total: 10},
+		{EstimateID: "b", Owner: "github:octocat", Name: "batch", Input: []byte(`{}`), Currency: "USD", Total: 20, ShareTokenHash: "h"},
+		{EstimateID: "c", Owner: "github:hubot", Name: "other", Input: []byte(`{}`), Currency: "USD", Total: 30},
+	} {
+		e.CreatedAt = created.Add(time.Duration(i) * time.Hour)
+		e.PricedAt = e.CreatedAt
+		if err := repo.InsertEstimate(ctx, e); err != nil {
+			t.Fatalf("insert %s: %v", e.EstimateID, err)
+		}
+	}
+
+	e, err := repo.EstimateByID(ctx, "a")
+	if err != nil || e.Name != "web" || string(e.Input) != `{"lines":[]}` || e.ShareTokenHash != "" || !e.CreatedAt.Equal(created) {
+		t.Fatalf("EstimateByID = %+v, %v", e, err)
+	}
+	if e, err := repo.EstimateByShareHash(ctx, "h"); err != nil || e.EstimateID != "b" {
+		t.Fatalf("EstimateByShareHash = %+v, %v", e, err)
+	}
+	list, err := repo.ListEstimates(ctx, "github:octocat")
+	if err != nil || len(list) != 2 || list[0].EstimateID != "b" {
+		t.Fatalf("ListEstimates = %+v, %v", list, err)
+	}
+	if all, err := repo.ListEstimates(ctx, ""); err != nil || len(all) != 3 {
+		t.Fatalf("ListEstimates of everyone = %d, %v", len(all), err)
+	}
+
+	priced := created.Add(48 * time.Hour)
+	if err := repo.UpdateEstimatePricing(ctx, "a", "USD", 12.5, priced); err != nil {
+		t.Fatalf("update pricing: %v", err)
+	}
+	if e, _ := repo.EstimateByID(ctx, "a"); e.Total != 12.5 || !e.PricedAt.Equal(priced) {
+		t.Fatalf("repriced estimate = %+v", e)
+	}
+
+	if err := repo.SetEstimateShareHash(ctx, "a", "github:hubot", "x"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("sharing another owner's estimate: got %v, want sql.ErrNoRows", err)
+	}
+	if err := repo.SetEstimateShareHash(ctx, "b", "github:octocat", ""); err != nil {
+		t.Fatalf("unshare: %v", err)
+	}
+	if _, err := repo.EstimateByShareHash(ctx, "h"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("revoked share token: got %v, want sql.ErrNoRows", err)
+	}
+
+	if err := repo.DeleteEstimate(ctx, "c", "github:octocat"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("deleting another owner's estimate: got %v, want sql.ErrNoRows", err)
+	}
+	if err := repo.DeleteEstimate(ctx, "c", "github:hubot"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+}
+
+func TestSQLRepository_EstimateDrift(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	for _, e := range []Estimate{
+		{EstimateID: "a", Owner: "github:octocat", Name: "web", Input: []byte(`{}`), Currency: "USD", Total: 10, CreatedAt: created, PricedAt: created},
+		{EstimateID: "b", Owner: "github:hubot", Name: "batch", Input: []byte(`{}`), Currency: "USD", Total: 20, CreatedAt: created, PricedAt: created},
+	} {
+		if err := repo.InsertEstimate(ctx, e); err != nil {
+			t.Fatalf("insert %s: %v", e.EstimateID, err)
+		}
+	}
+	day := 24 * time.Hour
+	for _, d := range []EstimateDrift{
+		{EstimateID: "a", Currency: "USD", PreviousTotal: 10, Total: 11, PreviousPricedAt: created, PricedAt: created.Add(day)},
+		{EstimateID: "a", Currency: "USD", PreviousTotal: 11, Total: 9, PreviousPricedAt: created.Add(day), PricedAt: created.Add(2 * day)},
+		{EstimateID: "b", Currency: "USD", PreviousTotal: 20, Total: 21, PreviousPricedAt: created, PricedAt: created.Add(day)},
+	} {
+		if err := repo.InsertEstimateDrift(ctx, d); err != nil {
+			t.Fatalf("insert drift: %v", err)
+		}
+	}
+
+	drift, err := repo.ListEstimateDrift(ctx, EstimateDriftFilter{Owner: "github:octocat"})
+	if err != nil || len(drift) != 2 || drift[0].Total != 9 || drift[0].Name != "web" || !drift[0].PreviousPricedAt.Equal(created.Add(day)) {
+		t.Fatalf("ListEstimateDrift by owner = %+v, %v", drift, err)
+	}
+	if drift, err := repo.ListEstimateDrift(ctx, EstimateDriftFilter{Since: created.Add(2 * day)}); err != nil || len(drift) != 1 {
+		t.Fatalf("ListEstimateDrift since = %+v, %v", drift, err)
+	}
+	if drift, err := repo.ListEstimateDrift(ctx, EstimateDriftFilter{EstimateID: "b", Limit: 5}); err != nil || len(drift) != 1 || drift[0].Total != 21 {
+		t.Fatalf("ListEstimateDrift by estimate = %+v, %v", drift, err)
+	}
+
+	if err := repo.DeleteEstimate(ctx, "a", "github:octocat"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if drift, err := repo.ListEstimateDrift(ctx, EstimateDriftFilter{}); err != nil || len(drift) != 1 {
+		t.Fatalf("drift after delete = %+v, %v", drift, err)
+	}
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"strings"
+)
+
+// UpsertMachineType inserts or updates a machine type.
+func (r *SQLRepository) UpsertMachineType(ctx context.Context, m MachineType) (err error) {
+	ctx, span := startSpan(ctx, "UpsertMachineType")
+	defer func() { endSpan(span, err) }()
+	_, err = r.db.ExecContext(ctx, `INSERT INTO machine_types (name, family, vcpus, billed_vcpus, memory_gb, gpus, gpu_type, local_ssd_gb, dataset_version)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT(name) DO UPDATE SET family=excluded.family, vcpus=excluded.vcpus, billed_vcpus=excluded.billed_vcpus, memory_gb=excluded.memory_gb, gpus=excluded.gpus, gpu_type=excluded.gpu_type, local_ssd_gb=excluded.local_ssd_gb, dataset_version=excluded.dataset_version`,
+		m.Name, m.Family, m.VCPUs, m.BilledVCPUs, m.MemoryGB, m.GPUs, m.GPUType, m.LocalSSDGB, m.Version)
+	return err
+}
+
+// DeleteStaleMachineTypes deletes the machine types loaded from a dataset
+// version other than version and returns how many were deleted.
+func (r *SQLRepository) DeleteStaleMachineTypes(ctx context.Context, version string) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "DeleteStaleMachineTypes")
+	defer func() { endSpan(span, err) }()
+	res, err := r.db.ExecContext(ctx, `DELETE FROM machine_types WHERE dataset_version != ?`, version)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// MachineTypeByName returns the machine type called name, or sql.ErrNoRows.
+func (r *SQLRepository) MachineTypeByName(ctx context.Context, name string) (_ MachineType, err error) {
+	ctx, span := startSpan(ctx, "MachineTypeByName")
+	defer func() { endSpan(span, err) }()
+	return scanMachineType(r.db.QueryRowContext(ctx, machineTypeQuery+` WHERE name = ?`, name))
+}
+
+// ListMachineTypes returns the machine types matching f ordered by family,
+// vCPUs and memory. Family matches case-insensitively.
+func (r *SQLRepository) ListMachineTypes(ctx context.Context, f MachineTypeFilter) (_ []MachineType, err error) {
+	ctx, span := startSpan(ctx, "ListMachineTypes")
+	defer func() { endSpan(span, err) }()
+	var (
+		where []string
+		args  []any
+	)
+	if f.Family != "" {
+		where = append(where, "family = ? COLLATE NOCASE")
+		args = append(args, f.Family)
+	}
+	if f.MinVCPUs > 0 {
+		where = append(where, "vcpus >= ?")
+		args = append(args, f.MinVCPUs)
+	}
+	if f.MaxVCPUs > 0 {
+		where = append(where, "vcpus <= ?")
+		args = append(args, f.MaxVCPUs)
+	}
+	if f.MinMemoryGB > 0 {
+		where = append(where, "memory_gb >= ?")
+		args = append(args, f.MinMemoryGB)
+	}
+	if f.MaxMemoryGB > 0 {
+		where = append(where, "memory_gb <= ?")
+		args = append(args, f.MaxMemoryGB)
+	}
+	query := machineTypeQuery
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	rows, err := r.db.QueryContext(ctx, query+" ORDER BY family, vcpus, memory_gb, name", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var types []MachineType
+	for rows.Next() {
+		m, err := scanMachineType(rows)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, m)
+	}
+	return types, rows.Err()
+}
+
+const machineTypeQuery = `SELECT name, family, vcpus, billed_vcpus, memory_gb, gpus, gpu_type, local_ssd_gb, dataset_version FROM machine_types`
+
+func scanMachineType(row rowScanner) (MachineType, error) {
+	var m MachineType
+	err := row.Scan(&m.Name, &m.Family, &m.VCPUs, &m.BilledVCPUs, &m.MemoryGB, &m.GPUs, &m.GPUType, &m.LocalSSDGB, &m.Version)
+	return m, err
+}
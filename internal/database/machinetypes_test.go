@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestSQLRepository_MachineTypes(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	for _, m := range []MachineType{
+		{Name: "n2-standard-8", Family: "n2", VCPUs: 8, BilledVCPUs: 8, MemoryGB: 32, Version: "v1"},
+		{Name: "e2-micro", Family: "e2", VCPUs: 2, BilledVCPUs: 0.25, MemoryGB: 1, Version: "v1"},
+		{Name: "n2-highmem-8", Family: "n2", VCPUs: 8, MemoryGB: 64, Version: "v1"},
+		{Name: "g2-standard-4", Family: "g2", VCPUs: 4, MemoryGB: 16, GPUs: 1, GPUType: "nvidia-l4", Version: "v1"},
+		{Name: "n2-standard-2", Family: "n2", VCPUs: 2, MemoryGB: 8, Version: "v2"},
+	} {
+		if err := repo.UpsertMachineType(ctx, m); err != nil {
+			t.Fatalf("upsert %s: %v", m.Name, err)
+		}
+	}
+
+	m, err := repo.MachineTypeByName(ctx, "g2-standard-4")
+	if err != nil || m.GPUs != 1 || m.GPUType != "nvidia-l4" || m.Version != "v1" {
+		t.Fatalf("MachineTypeByName = %+v, %v", m, err)
+	}
+	if m, err := repo.MachineTypeByName(ctx, "e2-micro"); err != nil || m.VCPUs != 2 || m.BilledVCPUs != 0.25 {
+		t.Fatalf("shared-core MachineTypeByName = %+v, %v", m, err)
+	}
+	if _, err := repo.MachineTypeByName(ctx, "n9-standard-1"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("unknown machine type: got %v, want sql.ErrNoRows", err)
+	}
+
+	types, err := repo.ListMachineTypes(ctx, MachineTypeFilter{Family: "N2", MinVCPUs: 4, MaxMemoryGB: 40})
+	if err != nil || len(types) != 1 || types[0].Name != "n2-standard-8" {
+		t.Fatalf("ListMachineTypes = %+v, %v", types, err)
+	}
+
+	n, err := repo.DeleteStaleMachineTypes(ctx, "v2")
+	if err != nil || n != 4 {
+		t.Fatalf("DeleteStaleMachineTypes = %d, %v", n, err)
+	}
+	if types, err := repo.ListMachineTypes(ctx, MachineTypeFilter{}); err != nil || len(types) != 1 {
+		t.Fatalf("machine types after delete = %+v, %v", types, err)
+	}
+}
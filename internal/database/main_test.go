@@ -22,13 +22,20 @@ func TestMain(m *testing.M) {
 	os.Exit(code)
 }
 
-func setupTestRepo(t *testing.T) Repository {
+func setupTestRepo(t *testing.T) *SQLRepository {
 	t.Helper()
 	stmts := []string{
 		"DELETE FROM pricing_info",
 		"DELETE FROM pricing_updates",
 		"DELETE FROM skus",
 		"DELETE FROM services",
+		"DELETE FROM api_keys",
+		"DELETE FROM tool_call_quotas",
+		"DELETE FROM tool_calls",
+		"DELETE FROM regions",
+		"DELETE FROM machine_types",
+		"DELETE FROM estimate_drift",
+		"DELETE FROM estimates",
 	}
 	for _, stmt := range stmts {
 		if _, err := testDB.Exec(stmt); err != nil {
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS tool_call_quotas (
+    principal TEXT NOT NULL,
+    day TEXT NOT NULL,
+    calls INTEGER NOT NULL,
+    PRIMARY KEY (principal, day)
+);
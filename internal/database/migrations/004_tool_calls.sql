@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS tool_calls (
+    call_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    called_at TIMESTAMP NOT NULL,
+    principal TEXT NOT NULL,
+    tool TEXT NOT NULL,
+    arguments BLOB,
+    result_bytes INTEGER NOT NULL,
+    error_code TEXT,
+    error_message TEXT,
+    latency_ms INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS tool_calls_called_at ON tool_calls (called_at);
+
+CREATE INDEX IF NOT EXISTS tool_calls_principal ON tool_calls (principal, called_at);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS regions (
+    region_id TEXT PRIMARY KEY,
+    display_name TEXT NOT NULL,
+    continent TEXT NOT NULL,
+    country TEXT NOT NULL,
+    multi_region INTEGER NOT NULL,
+    multi_regions BLOB NOT NULL,
+    zones BLOB NOT NULL
+);
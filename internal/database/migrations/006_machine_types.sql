@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS machine_types (
+    name TEXT PRIMARY KEY,
+    family TEXT NOT NULL,
+    vcpus INTEGER NOT NULL,
+    memory_gb REAL NOT NULL,
+    gpus INTEGER NOT NULL,
+    gpu_type TEXT NOT NULL,
+    local_ssd_gb REAL NOT NULL,
+    dataset_version TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS machine_types_family ON machine_types (family, vcpus);
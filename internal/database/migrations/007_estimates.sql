@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS estimates (
+    estimate_id TEXT PRIMARY KEY,
+    owner TEXT NOT NULL,
+    name TEXT NOT NULL,
+    input BLOB NOT NULL,
+    currency TEXT NOT NULL,
+    total REAL NOT NULL,
+    share_token_hash TEXT UNIQUE,
+    created_at TIMESTAMP NOT NULL,
+    priced_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS estimates_owner ON estimates (owner, created_at);
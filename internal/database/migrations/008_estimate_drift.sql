@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS estimate_drift (
+    drift_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    estimate_id TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    previous_total REAL NOT NULL,
+    total REAL NOT NULL,
+    previous_priced_at TIMESTAMP NOT NULL,
+    priced_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS estimate_drift_estimate ON estimate_drift (estimate_id, priced_at);
+CREATE INDEX IF NOT EXISTS estimate_drift_priced_at ON estimate_drift (priced_at);
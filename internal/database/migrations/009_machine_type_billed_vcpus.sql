@@ -0,0 +1,3 @@
+ALTER TABLE machine_types ADD COLUMN billed_vcpus REAL NOT NULL DEFAULT 0;
+
+UPDATE machine_types SET billed_vcpus = vcpus;
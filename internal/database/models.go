@@ -69,3 +69,129 @@ type PricingUpdate struct {
 	SkusUpdated     int
 	LogMessage      string
 }
+
+// APIKey is a named credential for service-to-service access.
+// Only the SHA-256 hash of the key is stored.
+type APIKey struct {
+	KeyID      int64
+	Name       string
+	KeyHash    string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	LastUsedAt time.Time // zero if the key has never been used
+	RevokedAt  time.Time // zero if the key is active
+}
+
+// Estimate is a saved bill of materials. Input holds the line items as
+// given to the estimate tool, so the estimate can be priced again.
+type Estimate struct {
+	EstimateID     string
+	Owner          string // principal that saved the estimate, such as github:octocat
+	Name           string
+	Input          []byte // JSON line items
+	Currency       string
+	Total          float64 // total when last priced
+	ShareTokenHash string  // SHA-256 of the read-only share token; empty when not shared
+	CreatedAt      time.Time
+	PricedAt       time.Time
+}
+
+// EstimateDrift records the change of an estimate's total between two
+// pricings.
+type EstimateDrift struct {
+	DriftID          int64
+	EstimateID       string
+	Name             string // name of the estimate, when listed
+	Currency         string
+	PreviousTotal    float64
+	Total            float64
+	PreviousPricedAt time.Time
+	PricedAt         time.Time
+}
+
+// EstimateDriftFilter selects estimate drift. Zero fields match everything.
+type EstimateDriftFilter struct {
+	Owner      string
+	EstimateID string
+	Since      time.Time
+	Limit      int
+}
+
+// ToolCall records one MCP tool invocation for auditing.
+type ToolCall struct {
+	CallID       int64
+	CalledAt     time.Time
+	Principal    string
+	Tool         string
+	Arguments    []byte // JSON arguments with sensitive values redacted
+	ResultBytes  int
+	ErrorCode    string // empty for successful calls
+	ErrorMessage string
+	Latency      time.Duration
+}
+
+// ToolCallFilter selects tool calls. Zero fields match everything.
+type ToolCallFilter struct {
+	Principal  string
+	Tool       string
+	Since      time.Time
+	Until      time.Time
+	ErrorsOnly bool
+	Limit      int
+}
+
+// Region describes a location SKUs are offered in, such as a Compute Engine
+// region or a multi-region like "us".
+type Region struct {
+	RegionID     string
+	DisplayName  string
+	Continent    string
+	Country      string   // empty for multi-regions spanning countries
+	MultiRegion  bool     // the region is itself a multi-region
+	MultiRegions []string // multi-regions this region belongs to
+	Zones        []string
+}
+
+// RegionFilter selects regions. Zero fields match everything.
+type RegionFilter struct {
+	Continent   string
+	Country     string
+	MultiRegion string // only regions belonging to this multi-region
+}
+
+// MachineType is a predefined Compute Engine machine type such as
+// "n2-standard-8".
+type MachineType struct {
+	Name        string
+	Family      string
+	VCPUs       int
+	BilledVCPUs float64 // fewer than VCPUs for shared-core types such as e2-micro
+	MemoryGB    float64
+	GPUs        int
+	GPUType     string // empty without GPUs
+	LocalSSDGB  float64
+	Version     string // version of the dataset the row was loaded from
+}
+
+// MachineTypeFilter selects machine types. Zero fields match everything.
+type MachineTypeFilter struct {
+	Family      string
+	MinVCPUs    int
+	MaxVCPUs    int
+	MinMemoryGB float64
+	MaxMemoryGB float64
+}
+
+// SKUFilter selects SKUs. Zero fields match everything.
+type SKUFilter struct {
+	Query string // matched against description and SKU name
+	// DescriptionPattern matches the whole description case-insensitively,
+	// with * matching any text.
+	DescriptionPattern string
+	ServiceID          string
+	ResourceFamily     string
+	ResourceGroup      string
+	UsageType          string
+	Region             string // only SKUs offered in this region
+	Limit              int
+}
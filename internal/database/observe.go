@@ -0,0 +1,81 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.32.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"mcp-server/internal/tracing"
+)
+
+// QueryObserver is told the kind ("select", "insert", ...), duration and
+// error of every statement a repository runs.
+type QueryObserver interface {
+	ObserveQuery(op string, d time.Duration, err error)
+}
+
+// observedDB times statements run through it when an observer is set.
+type observedDB struct {
+	*sql.DB
+	observer QueryObserver
+}
+
+func (db observedDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	start := time.Now()
+	res, err := db.DB.ExecContext(ctx, query, args...)
+	db.observe(query, start, err)
+	return res, err
+}
+
+func (db observedDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	db.observe(query, start, err)
+	return rows, err
+}
+
+func (db observedDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	start := time.Now()
+	row := db.DB.QueryRowContext(ctx, query, args...)
+	db.observe(query, start, row.Err())
+	return row
+}
+
+func (db observedDB) observe(query string, start time.Time, err error) {
+	if db.observer == nil {
+		return
+	}
+	db.observer.ObserveQuery(statementKind(query), time.Since(start), err)
+}
+
+// statementKind returns the lower-cased leading keyword of query.
+func statementKind(query string) string {
+	kind, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+	if i := strings.IndexAny(kind, "\n\t("); i >= 0 {
+		kind = kind[:i]
+	}
+	return strings.ToLower(kind)
+}
+
+var tracer = otel.Tracer("mcp-server/internal/database")
+
+// startSpan starts a client span for a repository method.
+func startSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "SQLRepository."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemNameSQLite))
+}
+
+// endSpan ends span, recording err unless it only reports a missing row.
+func endSpan(span trace.Span, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	tracing.End(span, err)
+}
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type recordingObserver struct{ ops []string }
+
+func (o *recordingObserver) ObserveQuery(op string, d time.Duration, err error) {
+	o.ops = append(o.ops, op)
+}
+
+func TestSQLRepository_SetQueryObserver(t *testing.T) {
+	repo := setupTestRepo(t)
+	obs := &recordingObserver{}
+	repo.SetQueryObserver(obs)
+	ctx := context.Background()
+	if err := repo.UpsertService(ctx, Service{ServiceID: "svc", DisplayName: "Svc", BusinessEntityName: "Ent"}); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	if _, err := repo.CatalogStats(ctx); err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	want := []string{"insert", "select", "select"}
+	if len(obs.ops) != len(want) {
+		t.Fatalf("observed %v, want %v", obs.ops, want)
+	}
+	for i := range want {
+		if obs.ops[i] != want[i] {
+			t.Fatalf("observed %v, want %v", obs.ops, want)
+		}
+	}
+}
+
+func TestSQLRepository_Spans(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	if _, err := repo.APIKeyByHash(ctx, "missing"); err == nil {
+		t.Fatal("expected missing key")
+	}
+	spans := rec.Ended()
+	if len(spans) != 1 || spans[0].Name() != "SQLRepository.APIKeyByHash" {
+		t.Fatalf("spans = %v", spans)
+	}
+	if spans[0].Status().Code == codes.Error {
+		t.Fatal("a missing row must not mark the span as failed")
+	}
+}
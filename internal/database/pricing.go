@@ -0,0 +1,141 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+)
+
+// SKUByID returns the SKU with the given ID, or sql.ErrNoRows.
+func (r *SQLRepository) SKUByID(ctx context.Context, skuID string) (_ SKU, err error) {
+	ctx, span := startSpan(ctx, "SKUByID")
+	defer func() { endSpan(span, err) }()
+	return scanSKU(r.db.QueryRowContext(ctx, skuQuery+` WHERE sku_id = ?`, skuID))
+}
+
+// SearchSKUs returns the SKUs matching f ordered by description, at most
+// f.Limit or 50. Every word of f.Query must appear in the description or
+// SKU name.
+func (r *SQLRepository) SearchSKUs(ctx context.Context, f SKUFilter) (_ []SKU, err error) {
+	ctx, span := startSpan(ctx, "SearchSKUs")
+	defer func() { endSpan(span, err) }()
+	var (
+		where []string
+		args  []any
+	)
+	for _, word := range strings.Fields(f.Query) {
+		where = append(where, "(description LIKE ? OR sku_name LIKE ?)")
+		args = append(args, "%"+word+"%", "%"+word+"%")
+	}
+	if f.DescriptionPattern != "" {
+		where = append(where, `description LIKE ? ESCAPE '\'`)
+		args = append(args, likePattern(f.DescriptionPattern))
+	}
+	if f.ServiceID != "" {
+		where = append(where, "service_id = ?")
+		args = append(args, f.ServiceID)
+	}
+	for _, c := range []struct{ field, value string }{
+		{"resourceFamily", f.ResourceFamily},
+		{"resourceGroup", f.ResourceGroup},
+		{"usageType", f.UsageType},
+	} {
+		if c.value != "" {
+			where = append(where, "json_extract(CAST(category AS TEXT), '$."+c.field+"') = ?")
+			args = append(args, c.value)
+		}
+	}
+	if f.Region != "" {
+		where = append(where, "EXISTS (SELECT 1 FROM json_each(CAST(skus.service_regions AS TEXT)) WHERE value = ?)")
+		args = append(args, f.Region)
+	}
+	query := skuQuery
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	limit := f.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := r.db.QueryContext(ctx, query+" ORDER BY description, sku_id LIMIT ?", append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var skus []SKU
+	for rows.Next() {
+		s, err := scanSKU(rows)
+		if err != nil {
+			return nil, err
+		}
+		skus = append(skus, s)
+	}
+	return skus, rows.Err()
+}
+
+// likePattern turns a pattern with * wildcards into a LIKE pattern with
+// backslash escapes.
+func likePattern(p string) string {
+	r := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`, "*", "%")
+	return r.Replace(p)
+}
+
+const skuQuery = `SELECT sku_id, service_id, sku_name, description, category, service_regions, geo_taxonomy FROM skus`
+
+func scanSKU(row rowScanner) (SKU, error) {
+	var (
+		s                 SKU
+		cat, regions, geo []byte
+	)
+	if err := row.Scan(&s.SKUID, &s.ServiceID, &s.SkuName, &s.Description, &cat, &regions, &geo); err != nil {
+		return SKU{}, err
+	}
+	if err := json.Unmarshal(cat, &s.Category); err != nil {
+		return SKU{}, err
+	}
+	if err := json.Unmarshal(regions, &s.ServiceRegions); err != nil {
+		return SKU{}, err
+	}
+	if err := json.Unmarshal(geo, &s.GeoTaxonomy); err != nil {
+		return SKU{}, err
+	}
+	return s, nil
+}
+
+// LatestPricingInfo returns the pricing of a SKU with the newest effective
+// time, or sql.ErrNoRows.
+func (r *SQLRepository) LatestPricingInfo(ctx context.Context, skuID string) (_ PricingInfo, err error) {
+	ctx, span := startSpan(ctx, "LatestPricingInfo")
+	defer func() { endSpan(span, err) }()
+	var (
+		p           PricingInfo
+		summary     sql.NullString
+		unitDesc    sql.NullString
+		displayQty  sql.NullInt64
+		tieredRates []byte
+	)
+	err = r.db.QueryRowContext(ctx, `SELECT pricing_info_id, sku_id, effective_time, summary, currency_code, usage_unit, usage_unit_description, display_quantity, tiered_rates
+FROM pricing_info WHERE sku_id = ? ORDER BY effective_time DESC LIMIT 1`, skuID).Scan(
+		&p.PricingInfoID, &p.SKUID, &p.EffectiveTime, &summary, &p.CurrencyCode, &p.UsageUnit, &unitDesc, &displayQty, &tieredRates)
+	if err != nil {
+		return PricingInfo{}, err
+	}
+	if err := json.Unmarshal(tieredRates, &p.TieredRates); err != nil {
+		return PricingInfo{}, err
+	}
+	p.Summary = summary.String
+	p.UsageUnitDescription = unitDesc.String
+	p.DisplayQuantity = displayQty.Int64
+	return p, nil
+}
+
+// LatestUpdateID returns the ID of the most recent pricing_updates row, or 0
+// when no sync has been recorded. It changes whenever a sync finishes.
+func (r *SQLRepository) LatestUpdateID(ctx context.Context) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "LatestUpdateID")
+	defer func() { endSpan(span, err) }()
+	var id int64
+	err = r.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(update_id), 0) FROM pricing_updates`).Scan(&id)
+	return id, err
+}
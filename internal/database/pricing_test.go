@@ -0,0 +1,139 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSQLRepository_SKUByID(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	if _, err := repo.SKUByID(ctx, "missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("missing SKU: got %v, want sql.ErrNoRows", err)
+	}
+	if err := repo.UpsertService(ctx, Service{ServiceID: "svc", DisplayName: "Svc", BusinessEntityName: "Ent"}); err != nil {
+		t.Fatalf("service: %v", err)
+	}
+	want := SKU{
+		SKUID: "sku1", ServiceID: "svc", SkuName: "SKU One", Description: "N1 core",
+		Category:       Category{ResourceFamily: "Compute", UsageType: "OnDemand"},
+		ServiceRegions: []string{"us-central1"},
+		GeoTaxonomy:    GeoTaxonomy{Type: "REGIONAL", Regions: []string{"us-central1"}},
+	}
+	if err := repo.UpsertSKU(ctx, want); err != nil {
+		t.Fatalf("sku: %v", err)
+	}
+	got, err := repo.SKUByID(ctx, "sku1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Description != want.Description || got.Category != want.Category || len(got.ServiceRegions) != 1 || got.GeoTaxonomy.Type != "REGIONAL" {
+		t.Fatalf("SKU = %+v, want %+v", got, want)
+	}
+}
+
+func TestSQLRepository_LatestPricingInfo(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	if _, err := repo.LatestPricingInfo(ctx, "sku1"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("no pricing: got %v, want sql.ErrNoRows", err)
+	}
+	if err := repo.UpsertService(ctx, Service{ServiceID: "svc", DisplayName: "Svc", BusinessEntityName: "Ent"}); err != nil {
+		t.Fatalf("service: %v", err)
+	}
+	if err := repo.UpsertSKU(ctx, SKU{SKUID: "sku1", ServiceID: "svc", SkuName: "SKU One", Description: "desc"}); err != nil {
+		t.Fatalf("sku: %v", err)
+	}
+	newest := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	for i, at := range []time.Time{newest, newest.AddDate(0, -1, 0)} {
+		p := PricingInfo{SKUID: "sku1", EffectiveTime: at, CurrencyCode: "USD", UsageUnit: "h",
+			TieredRates: []TieredRate{{UnitPrice: Money{CurrencyCode: "USD", Nanos: int32(1000 * (i + 1))}}}}
+		if err := repo.UpsertPricingInfo(ctx, p); err != nil {
+			t.Fatalf("pricing: %v", err)
+		}
+	}
+	p, err := repo.LatestPricingInfo(ctx, "sku1")
+	if err != nil {
+		t.Fatalf("latest: %v", err)
+	}
+	if !p.EffectiveTime.Equal(newest) || len(p.TieredRates) != 1 || p.TieredRates[0].UnitPrice.Nanos != 1000 {
+		t.Fatalf("latest pricing = %+v", p)
+	}
+}
+
+func TestSQLRepository_LatestUpdateID(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	if id, err := repo.LatestUpdateID(ctx); err != nil || id != 0 {
+		t.Fatalf("empty table: id %d, %v", id, err)
+	}
+	if err := repo.InsertPricingUpdate(ctx, PricingUpdate{UpdateTime: time.Now(), Status: "SUCCESS"}); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	first, err := repo.LatestUpdateID(ctx)
+	if err != nil || first == 0 {
+		t.Fatalf("after one sync: id %d, %v", first, err)
+	}
+	if err := repo.InsertPricingUpdate(ctx, PricingUpdate{UpdateTime: time.Now(), Status: "SUCCESS"}); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if second, err := repo.LatestUpdateID(ctx); err != nil || second <= first {
+		t.Fatalf("after two syncs: id %d (was %d), %v", second, first, err)
+	}
+}
+
+func TestSQLRepository_SearchSKUs(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	if err := repo.UpsertService(ctx, Service{ServiceID: "compute", DisplayName: "Compute Engine", BusinessEntityName: "GCP"}); err != nil {
+		t.Fatalf("service: %v", err)
+	}
+	for _, s := range []SKU{
+		{SKUID: "cpu-us", ServiceID: "compute", SkuName: "n2", Description: "N2 Instance Core running in Americas",
+			Category: Category{ResourceFamily: "Compute"}, ServiceRegions: []string{"us-central1"}},
+		{SKUID: "cpu-eu", ServiceID: "compute", SkuName: "n2", Description: "N2 Instance Core running in EMEA",
+			Category: Category{ResourceFamily: "Compute"}, ServiceRegions: []string{"europe-west1"}},
+		{SKUID: "disk-us", ServiceID: "compute", SkuName: "pd", Description: "Storage PD Capacity",
+			Category: Category{ResourceFamily: "Storage"}, ServiceRegions: []string{"us-central1"}},
+	} {
+		if err := repo.UpsertSKU(ctx, s); err != nil {
+			t.Fatalf("sku: %v", err)
+		}
+	}
+	ids := func(f SKUFilter) []string {
+		t.Helper()
+		skus, err := repo.SearchSKUs(ctx, f)
+		if err != nil {
+			t.Fatalf("search %+v: %v", f, err)
+		}
+		var out []string
+		for _, s := range skus {
+			out = append(out, s.SKUID)
+		}
+		return out
+	}
+	if got := ids(SKUFilter{Query: "n2 core"}); len(got) != 2 {
+		t.Fatalf("query = %v, want both cores", got)
+	}
+	if got := ids(SKUFilter{Query: "core", Region: "europe-west1"}); len(got) != 1 || got[0] != "cpu-eu" {
+		t.Fatalf("region filter = %v", got)
+	}
+	if got := ids(SKUFilter{ResourceFamily: "Storage"}); len(got) != 1 || got[0] != "disk-us" {
+		t.Fatalf("resource family filter = %v", got)
+	}
+	if got := ids(SKUFilter{DescriptionPattern: "n2 instance core running in *"}); len(got) != 2 {
+		t.Fatalf("pattern = %v, want both cores", got)
+	}
+	if got := ids(SKUFilter{DescriptionPattern: "N2"}); len(got) != 0 {
+		t.Fatalf("pattern without wildcard = %v, want whole-description match only", got)
+	}
+	if got := ids(SKUFilter{DescriptionPattern: "100%*"}); len(got) != 0 {
+		t.Fatalf("pattern with %% = %v, want it matched literally", got)
+	}
+	if got := ids(SKUFilter{Limit: 1}); len(got) != 1 {
+		t.Fatalf("limit = %v", got)
+	}
+}
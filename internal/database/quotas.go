@@ -0,0 +1,28 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ErrQuotaExceeded is returned when a daily quota has been used up.
+var ErrQuotaExceeded = errors.New("daily quota exceeded")
+
+// IncrementToolCalls counts one tool call by principal on the UTC day of at,
+// provided fewer than limit calls were already counted that day. It returns
+// the updated count, or ErrQuotaExceeded without counting the call.
+func (r *SQLRepository) IncrementToolCalls(ctx context.Context, principal string, at time.Time, limit int) (_ int, err error) {
+	ctx, span := startSpan(ctx, "IncrementToolCalls")
+	defer func() { endSpan(span, err) }()
+	day := at.UTC().Format(time.DateOnly)
+	var calls int
+	err = r.db.QueryRowContext(ctx, `INSERT INTO tool_call_quotas (principal, day, calls) VALUES (?, ?, 1)
+ON CONFLICT(principal, day) DO UPDATE SET calls = calls + 1 WHERE calls < ?
+RETURNING calls`, principal, day, limit).Scan(&calls)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrQuotaExceeded
+	}
+	return calls, err
+}
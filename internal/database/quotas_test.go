@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSQLRepository_IncrementToolCalls(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	day := time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC)
+	for want := 1; want <= 2; want++ {
+		got, err := repo.IncrementToolCalls(ctx, "github:octocat", day, 2)
+		if err != nil {
+			t.Fatalf("call %d: %v", want, err)
+		}
+		if got != want {
+			t.Fatalf("count = %d, want %d", got, want)
+		}
+	}
+	if _, err := repo.IncrementToolCalls(ctx, "github:octocat", day, 2); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("third call: got %v, want ErrQuotaExceeded", err)
+	}
+	if got, err := repo.IncrementToolCalls(ctx, "apikey:ci", day, 2); err != nil || got != 1 {
+		t.Fatalf("other principal = %d, %v; want 1", got, err)
+	}
+	if got, err := repo.IncrementToolCalls(ctx, "github:octocat", day.Add(2*time.Hour), 2); err != nil || got != 1 {
+		t.Fatalf("next day = %d, %v; want 1", got, err)
+	}
+}
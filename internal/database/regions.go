@@ -0,0 +1,120 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// UpsertRegion inserts or updates a region.
+func (r *SQLRepository) UpsertRegion(ctx context.Context, g Region) (err error) {
+	ctx, span := startSpan(ctx, "UpsertRegion")
+	defer func() { endSpan(span, err) }()
+	multi, err := json.Marshal(nonNil(g.MultiRegions))
+	if err != nil {
+		return err
+	}
+	zones, err := json.Marshal(nonNil(g.Zones))
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `INSERT INTO regions (region_id, display_name, continent, country, multi_region, multi_regions, zones)
+VALUES (?, ?, ?, ?, ?, ?, ?) ON CONFLICT(region_id) DO UPDATE SET display_name=excluded.display_name, continent=excluded.continent, country=excluded.country, multi_region=excluded.multi_region, multi_regions=excluded.multi_regions, zones=excluded.zones`,
+		g.RegionID, g.DisplayName, g.Continent, g.Country, g.MultiRegion, multi, zones)
+	return err
+}
+
+// RegionByID returns the region with the given ID, or sql.ErrNoRows.
+func (r *SQLRepository) RegionByID(ctx context.Context, regionID string) (_ Region, err error) {
+	ctx, span := startSpan(ctx, "RegionByID")
+	defer func() { endSpan(span, err) }()
+	return scanRegion(r.db.QueryRowContext(ctx, regionQuery+` WHERE region_id = ?`, regionID))
+}
+
+// ListRegions returns the regions matching f ordered by ID. Continent and
+// country match case-insensitively.
+func (r *SQLRepository) ListRegions(ctx context.Context, f RegionFilter) (_ []Region, err error) {
+	ctx, span := startSpan(ctx, "ListRegions")
+	defer func() { endSpan(span, err) }()
+	var (
+		where []string
+		args  []any
+	)
+	if f.Continent != "" {
+		where = append(where, "continent = ? COLLATE NOCASE")
+		args = append(args, f.Continent)
+	}
+	if f.Country != "" {
+		where = append(where, "country = ? COLLATE NOCASE")
+		args = append(args, f.Country)
+	}
+	if f.MultiRegion != "" {
+		where = append(where, "EXISTS (SELECT 1 FROM json_each(CAST(regions.multi_regions AS TEXT)) WHERE value = ?)")
+		args = append(args, f.MultiRegion)
+	}
+	query := regionQuery
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	rows, err := r.db.QueryContext(ctx, query+" ORDER BY region_id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var regions []Region
+	for rows.Next() {
+		g, err := scanRegion(rows)
+		if err != nil {
+			return nil, err
+		}
+		regions = append(regions, g)
+	}
+	return regions, rows.Err()
+}
+
+// SKURegions returns the distinct regions named in skus.service_regions.
+func (r *SQLRepository) SKURegions(ctx context.Context) (_ []string, err error) {
+	ctx, span := startSpan(ctx, "SKURegions")
+	defer func() { endSpan(span, err) }()
+	rows, err := r.db.QueryContext(ctx, `SELECT DISTINCT j.value FROM skus, json_each(CAST(skus.service_regions AS TEXT)) AS j WHERE j.type = 'text' ORDER BY j.value`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+const regionQuery = `SELECT region_id, display_name, continent, country, multi_region, multi_regions, zones FROM regions`
+
+func scanRegion(row rowScanner) (Region, error) {
+	var (
+		g            Region
+		multi, zones []byte
+	)
+	if err := row.Scan(&g.RegionID, &g.DisplayName, &g.Continent, &g.Country, &g.MultiRegion, &multi, &zones); err != nil {
+		return Region{}, err
+	}
+	if err := json.Unmarshal(multi, &g.MultiRegions); err != nil {
+		return Region{}, err
+	}
+	if err := json.Unmarshal(zones, &g.Zones); err != nil {
+		return Region{}, err
+	}
+	return g, nil
+}
+
+// nonNil stores missing lists as [] rather than null.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestSQLRepository_Regions(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	for _, g := range []Region{
+		{RegionID: "us", DisplayName: "United States", Continent: "North America", Country: "United States", MultiRegion: true},
+		{RegionID: "us-central1", DisplayName: "Iowa", Continent: "North America", Country: "United States", MultiRegions: []string{"us"}, Zones: []string{"us-central1-a"}},
+		{RegionID: "europe-west1", DisplayName: "Belgium", Continent: "Europe", Country: "Belgium", MultiRegions: []string{"europe"}},
+	} {
+		if err := repo.UpsertRegion(ctx, g); err != nil {
+			t.Fatalf("upsert %s: %v", g.RegionID, err)
+		}
+	}
+
+	g, err := repo.RegionByID(ctx, "us-central1")
+	if err != nil || g.DisplayName != "Iowa" || !slices.Equal(g.Zones, []string{"us-central1-a"}) || !slices.Equal(g.MultiRegions, []string{"us"}) {
+		t.Fatalf("RegionByID = %+v, %v", g, err)
+	}
+	if _, err := repo.RegionByID(ctx, "mars-north1"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("unknown region: got %v, want sql.ErrNoRows", err)
+	}
+
+	ids := func(f RegionFilter) []string {
+		t.Helper()
+		regions, err := repo.ListRegions(ctx, f)
+		if err != nil {
+			t.Fatalf("list %+v: %v", f, err)
+		}
+		var out []string
+		for _, g := range regions {
+			out = append(out, g.RegionID)
+		}
+		return out
+	}
+	if got := ids(RegionFilter{}); !slices.Equal(got, []string{"europe-west1", "us", "us-central1"}) {
+		t.Fatalf("all regions = %v", got)
+	}
+	if got := ids(RegionFilter{Continent: "europe"}); !slices.Equal(got, []string{"europe-west1"}) {
+		t.Fatalf("European regions = %v", got)
+	}
+	if got := ids(RegionFilter{MultiRegion: "us"}); !slices.Equal(got, []string{"us-central1"}) {
+		t.Fatalf("regions in us = %v", got)
+	}
+}
+
+func TestSQLRepository_SKURegions(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	if err := repo.UpsertService(ctx, Service{ServiceID: "svc", DisplayName: "Svc", BusinessEntityName: "Ent"}); err != nil {
+		t.Fatalf("service: %v", err)
+	}
+	for id, regions := range map[string][]string{"a": {"us-east1", "us-central1"}, "b": {"us-central1"}, "c": nil} {
+		if err := repo.UpsertSKU(ctx, SKU{SKUID: id, ServiceID: "svc", SkuName: id, Description: id, ServiceRegions: regions}); err != nil {
+			t.Fatalf("sku: %v", err)
+		}
+	}
+	got, err := repo.SKURegions(ctx)
+	if err != nil || !slices.Equal(got, []string{"us-central1", "us-east1"}) {
+		t.Fatalf("SKURegions = %v, %v", got, err)
+	}
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ReplicaOptions configures an embedded replica of a Turso primary.
+type ReplicaOptions struct {
+	// Path is the local replica file, created on first use.
+	Path string
+	// PrimaryURL is the libsql:// URL of the primary database.
+	PrimaryURL string
+	AuthToken  string
+	// SyncInterval is how often the replica pulls from the primary in the
+	// background; zero syncs only when Sync is called.
+	SyncInterval time.Duration
+	// ReadOnly sets the query_only pragma on every connection, like
+	// ConnectReadOnly does for local files.
+	ReadOnly bool
+}
+
+// Replica is a local copy of a Turso database. Reads are served from the
+// local file; writes are forwarded to the primary and visible locally once
+// they have been synced back.
+type Replica struct {
+	DB     *sql.DB
+	syncer replicaSyncer
+}
+
+type replicaSyncer interface {
+	sync() (frames int, err error)
+}
+
+// ConnectReplica opens an embedded replica, syncs it once so it starts from
+// the primary's current state, and enables foreign key enforcement.
+func ConnectReplica(ctx context.Context, opts ReplicaOptions) (*Replica, error) {
+	if opts.Path == "" || opts.PrimaryURL == "" {
+		return nil, errors.New("embedded replica needs a local path and a primary URL")
+	}
+	r, err := openReplica(opts)
+	if err != nil {
+		return nil, fmt.Errorf("open replica %s: %w", opts.Path, err)
+	}
+	if err := r.Sync(ctx); err != nil {
+		r.Close()
+		return nil, err
+	}
+	if _, err := r.DB.ExecContext(ctx, "PRAGMA foreign_keys = ON"); err != nil {
+		r.Close()
+		return nil, fmt.Errorf("enable foreign keys: %w", err)
+	}
+	return r, nil
+}
+
+// Sync pulls the changes committed on the primary since the last sync.
+func (r *Replica) Sync(ctx context.Context) (err error) {
+	ctx, span := tracer.Start(ctx, "Replica.Sync", trace.WithSpanKind(trace.SpanKindClient))
+	defer func() { endSpan(span, err) }()
+	start := time.Now()
+	frames, err := r.syncer.sync()
+	if err != nil {
+		return fmt.Errorf("sync replica: %w", err)
+	}
+	slog.DebugContext(ctx, "synced replica", "frames", frames, "duration", time.Since(start).String())
+	return nil
+}
+
+// UpdateSource reports the latest sync run recorded in a database.
+type UpdateSource interface {
+	LatestUpdateID(ctx context.Context) (int64, error)
+}
+
+var _ UpdateSource = (*SQLRepository)(nil)
+
+// FollowUpdates checks primary every interval until ctx is done and syncs the
+// replica as soon as the primary records a sync run the replica does not
+// have yet, so a finished sync is served within interval rather than within
+// the background sync interval. A failed check or sync is logged and retried
+// at the next check.
+func (r *Replica) FollowUpdates(ctx context.Context, primary UpdateSource, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		if _, err := r.syncIfBehind(ctx, primary); err != nil && ctx.Err() == nil {
+			slog.WarnContext(ctx, "follow primary updates", "error", err)
+		}
+	}
+}
+
+// syncIfBehind syncs the replica when primary has a newer sync run and
+// reports whether it did.
+func (r *Replica) syncIfBehind(ctx context.Context, primary UpdateSource) (bool, error) {
+	want, err := primary.LatestUpdateID(ctx)
+	if err != nil {
+		return false, fmt.Errorf("check primary: %w", err)
+	}
+	have, err := NewRepository(r.DB).LatestUpdateID(ctx)
+	if err != nil {
+		return false, fmt.Errorf("check replica: %w", err)
+	}
+	if have >= want {
+		return false, nil
+	}
+	slog.InfoContext(ctx, "replica behind primary, syncing", "replica_update_id", have, "primary_update_id", want)
+	return true, r.Sync(ctx)
+}
+
+// Close closes the database, which also stops background syncing.
+func (r *Replica) Close() error {
+	return r.DB.Close()
+}
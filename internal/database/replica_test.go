@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeSyncer struct {
+	calls int
+	err   error
+}
+
+func (f *fakeSyncer) sync() (int, error) {
+	f.calls++
+	return 3, f.err
+}
+
+func TestReplica_Sync(t *testing.T) {
+	s := &fakeSyncer{}
+	r := &Replica{DB: testDB, syncer: s}
+	if err := r.Sync(context.Background()); err != nil || s.calls != 1 {
+		t.Fatalf("sync: %v after %d calls", err, s.calls)
+	}
+	s.err = errors.New("primary unreachable")
+	if err := r.Sync(context.Background()); !errors.Is(err, s.err) {
+		t.Fatalf("sync error = %v, want %v", err, s.err)
+	}
+}
+
+type fixedUpdate int64
+
+func (f fixedUpdate) LatestUpdateID(ctx context.Context) (int64, error) {
+	return int64(f), nil
+}
+
+func TestReplica_SyncIfBehind(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	if err := repo.InsertPricingUpdate(ctx, PricingUpdate{UpdateTime: time.Now().UTC(), Status: "SUCCESS"}); err != nil {
+		t.Fatalf("insert update: %v", err)
+	}
+	have, err := repo.LatestUpdateID(ctx)
+	if err != nil {
+		t.Fatalf("latest update: %v", err)
+	}
+	s := &fakeSyncer{}
+	r := &Replica{DB: testDB, syncer: s}
+	if synced, err := r.syncIfBehind(ctx, fixedUpdate(have)); err != nil || synced || s.calls != 0 {
+		t.Fatalf("up to date: synced %v, %v after %d calls", synced, err, s.calls)
+	}
+	if synced, err := r.syncIfBehind(ctx, fixedUpdate(have+1)); err != nil || !synced || s.calls != 1 {
+		t.Fatalf("behind: synced %v, %v after %d calls", synced, err, s.calls)
+	}
+}
+
+func TestConnectReplica_RequiresPathAndPrimary(t *testing.T) {
+	for _, opts := range []ReplicaOptions{
+		{PrimaryURL: "libsql://db.turso.io"},
+		{Path: "replica.db"},
+	} {
+		if _, err := ConnectReplica(context.Background(), opts); err == nil {
+			t.Fatalf("ConnectReplica(%+v) succeeded", opts)
+		}
+	}
+}
@@ -12,27 +12,40 @@ type Repository interface {
 	UpsertSKU(ctx context.Context, s SKU) error
 	UpsertPricingInfo(ctx context.Context, p PricingInfo) error
 	InsertPricingUpdate(ctx context.Context, u PricingUpdate) error
+	UpsertRegion(ctx context.Context, g Region) error
+	SKURegions(ctx context.Context) ([]string, error)
+	UpsertMachineType(ctx context.Context, m MachineType) error
+	DeleteStaleMachineTypes(ctx context.Context, version string) (int64, error)
 }
 
 // SQLRepository implements Repository using an SQL database.
 type SQLRepository struct {
-	db *sql.DB
+	db observedDB
 }
 
 // NewRepository creates a new SQLRepository.
 func NewRepository(db *sql.DB) *SQLRepository {
-	return &SQLRepository{db: db}
+	return &SQLRepository{db: observedDB{DB: db}}
+}
+
+// SetQueryObserver reports every statement the repository runs to o.
+func (r *SQLRepository) SetQueryObserver(o QueryObserver) {
+	r.db.observer = o
 }
 
 // UpsertService inserts or updates a service.
-func (r *SQLRepository) UpsertService(ctx context.Context, s Service) error {
-	_, err := r.db.ExecContext(ctx, `INSERT INTO services (service_id, display_name, business_entity_name)
+func (r *SQLRepository) UpsertService(ctx context.Context, s Service) (err error) {
+	ctx, span := startSpan(ctx, "UpsertService")
+	defer func() { endSpan(span, err) }()
+	_, err = r.db.ExecContext(ctx, `INSERT INTO services (service_id, display_name, business_entity_name)
 VALUES (?, ?, ?) ON CONFLICT(service_id) DO UPDATE SET display_name=excluded.display_name, business_entity_name=excluded.business_entity_name`, s.ServiceID, s.DisplayName, s.BusinessEntityName)
 	return err
 }
 
 // UpsertSKU inserts or updates a SKU.
-func (r *SQLRepository) UpsertSKU(ctx context.Context, s SKU) error {
+func (r *SQLRepository) UpsertSKU(ctx context.Context, s SKU) (err error) {
+	ctx, span := startSpan(ctx, "UpsertSKU")
+	defer func() { endSpan(span, err) }()
 	cat, err := json.Marshal(s.Category)
 	if err != nil {
 		return err
@@ -51,7 +64,9 @@ VALUES (?, ?, ?, ?, ?, ?, ?) ON CONFLICT(sku_id) DO UPDATE SET service_id=exclud
 }
 
 // UpsertPricingInfo inserts or updates pricing info for a SKU.
-func (r *SQLRepository) UpsertPricingInfo(ctx context.Context, p PricingInfo) error {
+func (r *SQLRepository) UpsertPricingInfo(ctx context.Context, p PricingInfo) (err error) {
+	ctx, span := startSpan(ctx, "UpsertPricingInfo")
+	defer func() { endSpan(span, err) }()
 	rates, err := json.Marshal(p.TieredRates)
 	if err != nil {
 		return err
@@ -62,8 +77,10 @@ VALUES (?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT(sku_id, effective_time) DO UPDATE SE
 }
 
 // InsertPricingUpdate records a sync run.
-func (r *SQLRepository) InsertPricingUpdate(ctx context.Context, u PricingUpdate) error {
-	_, err := r.db.ExecContext(ctx, `INSERT INTO pricing_updates (update_time, status, services_updated, skus_updated, log_message)
+func (r *SQLRepository) InsertPricingUpdate(ctx context.Context, u PricingUpdate) (err error) {
+	ctx, span := startSpan(ctx, "InsertPricingUpdate")
+	defer func() { endSpan(span, err) }()
+	_, err = r.db.ExecContext(ctx, `INSERT INTO pricing_updates (update_time, status, services_updated, skus_updated, log_message)
 VALUES (?, ?, ?, ?, ?)`, u.UpdateTime, u.Status, u.ServicesUpdated, u.SkusUpdated, u.LogMessage)
 	return err
 }
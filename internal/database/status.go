@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// CatalogStats summarizes the pricing catalog.
+type CatalogStats struct {
+	Services    int
+	SKUs        int
+	PricingRows int
+	NewestPrice time.Time // latest effective_time; zero when the catalog is empty
+}
+
+// Ping reports whether the database is reachable.
+func (r *SQLRepository) Ping(ctx context.Context) (err error) {
+	ctx, span := startSpan(ctx, "Ping")
+	defer func() { endSpan(span, err) }()
+	return r.db.PingContext(ctx)
+}
+
+// PendingMigrations returns the embedded migrations not yet applied.
+func (r *SQLRepository) PendingMigrations(ctx context.Context) (_ []string, err error) {
+	ctx, span := startSpan(ctx, "PendingMigrations")
+	defer func() { endSpan(span, err) }()
+	return PendingMigrations(ctx, r.db.DB)
+}
+
+// LatestPricingUpdate returns the most recent sync run, restricted to runs
+// with the given status unless status is empty. It returns sql.ErrNoRows when
+// there is none.
+func (r *SQLRepository) LatestPricingUpdate(ctx context.Context, status string) (_ PricingUpdate, err error) {
+	ctx, span := startSpan(ctx, "LatestPricingUpdate")
+	defer func() { endSpan(span, err) }()
+	query := `SELECT update_id, update_time, status, services_updated, skus_updated, log_message FROM pricing_updates`
+	var args []any
+	if status != "" {
+		query += ` WHERE status = ?`
+		args = append(args, status)
+	}
+	query += ` ORDER BY update_time DESC, update_id DESC LIMIT 1`
+	var (
+		u              PricingUpdate
+		services, skus sql.NullInt64
+		message        sql.NullString
+	)
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&u.UpdateID, &u.UpdateTime, &u.Status, &services, &skus, &message)
+	if err != nil {
+		return PricingUpdate{}, err
+	}
+	u.ServicesUpdated = int(services.Int64)
+	u.SkusUpdated = int(skus.Int64)
+	u.LogMessage = message.String
+	return u, nil
+}
+
+// CatalogStats counts services, SKUs and pricing rows and finds the newest
+// effective price.
+func (r *SQLRepository) CatalogStats(ctx context.Context) (_ CatalogStats, err error) {
+	ctx, span := startSpan(ctx, "CatalogStats")
+	defer func() { endSpan(span, err) }()
+	var s CatalogStats
+	err = r.db.QueryRowContext(ctx, `SELECT
+    (SELECT COUNT(*) FROM services),
+    (SELECT COUNT(*) FROM skus),
+    (SELECT COUNT(*) FROM pricing_info)`).Scan(&s.Services, &s.SKUs, &s.PricingRows)
+	if err != nil {
+		return CatalogStats{}, err
+	}
+	err = r.db.QueryRowContext(ctx, `SELECT effective_time FROM pricing_info ORDER BY effective_time DESC LIMIT 1`).Scan(&s.NewestPrice)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return CatalogStats{}, err
+	}
+	return s, nil
+}
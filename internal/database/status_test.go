@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSQLRepository_LatestPricingUpdate(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	if _, err := repo.LatestPricingUpdate(ctx, ""); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("empty table: got %v, want sql.ErrNoRows", err)
+	}
+	base := time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC)
+	for i, status := range []string{"SUCCESS", "FAILED"} {
+		u := PricingUpdate{UpdateTime: base.Add(time.Duration(i) * time.Hour), Status: status, ServicesUpdated: 2, SkusUpdated: 5}
+		if err := repo.InsertPricingUpdate(ctx, u); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+	latest, err := repo.LatestPricingUpdate(ctx, "")
+	if err != nil || latest.Status != "FAILED" {
+		t.Fatalf("latest = %+v, %v; want FAILED run", latest, err)
+	}
+	ok, err := repo.LatestPricingUpdate(ctx, "SUCCESS")
+	if err != nil || !ok.UpdateTime.Equal(base) || ok.SkusUpdated != 5 {
+		t.Fatalf("latest success = %+v, %v", ok, err)
+	}
+}
+
+func TestSQLRepository_CatalogStats(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	s, err := repo.CatalogStats(ctx)
+	if err != nil || s != (CatalogStats{}) {
+		t.Fatalf("empty stats = %+v, %v", s, err)
+	}
+	if err := repo.UpsertService(ctx, Service{ServiceID: "svc", DisplayName: "Svc", BusinessEntityName: "Ent"}); err != nil {
+		t.Fatalf("service: %v", err)
+	}
+	if err := repo.UpsertSKU(ctx, SKU{SKUID: "sku1", ServiceID: "svc", SkuName: "SKU One", Description: "desc"}); err != nil {
+		t.Fatalf("sku: %v", err)
+	}
+	newest := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	for _, at := range []time.Time{newest.AddDate(0, -1, 0), newest} {
+		if err := repo.UpsertPricingInfo(ctx, PricingInfo{SKUID: "sku1", EffectiveTime: at, CurrencyCode: "USD", UsageUnit: "h"}); err != nil {
+			t.Fatalf("pricing: %v", err)
+		}
+	}
+	s, err = repo.CatalogStats(ctx)
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	if s.Services != 1 || s.SKUs != 1 || s.PricingRows != 2 || !s.NewestPrice.Equal(newest) {
+		t.Fatalf("stats = %+v", s)
+	}
+}
+
+func TestPendingMigrations(t *testing.T) {
+	pending, err := PendingMigrations(context.Background(), testDB)
+	if err != nil {
+		t.Fatalf("pending: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("pending after Migrate = %v, want none", pending)
+	}
+	if _, err := testDB.Exec("DELETE FROM schema_migrations WHERE name = '004_tool_calls.sql'"); err != nil {
+		t.Fatal(err)
+	}
+	pending, err = PendingMigrations(context.Background(), testDB)
+	if err != nil || len(pending) != 1 || pending[0] != "004_tool_calls.sql" {
+		t.Fatalf("pending = %v, %v; want 004_tool_calls.sql", pending, err)
+	}
+	if err := Migrate(testDB); err != nil {
+		t.Fatalf("re-migrate: %v", err)
+	}
+}
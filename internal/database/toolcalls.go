@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// DefaultToolCallLimit caps the number of tool calls returned when the filter has no limit.
+const DefaultToolCallLimit = 100
+
+// InsertToolCall records a tool invocation.
+func (r *SQLRepository) InsertToolCall(ctx context.Context, c ToolCall) (err error) {
+	ctx, span := startSpan(ctx, "InsertToolCall")
+	defer func() { endSpan(span, err) }()
+	_, err = r.db.ExecContext(ctx, `INSERT INTO tool_calls (called_at, principal, tool, arguments, result_bytes, error_code, error_message, latency_ms)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, c.CalledAt.UTC(), c.Principal, c.Tool, c.Arguments, c.ResultBytes, nullString(c.ErrorCode), nullString(c.ErrorMessage), c.Latency.Milliseconds())
+	return err
+}
+
+// ListToolCalls returns the tool calls matching f, newest first. Since and
+// Until may be in any time zone; called_at is stored in UTC text and compared
+// as text, so they are converted to UTC first.
+func (r *SQLRepository) ListToolCalls(ctx context.Context, f ToolCallFilter) (_ []ToolCall, err error) {
+	ctx, span := startSpan(ctx, "ListToolCalls")
+	defer func() { endSpan(span, err) }()
+	var where []string
+	var args []any
+	if f.Principal != "" {
+		where = append(where, "principal = ?")
+		args = append(args, f.Principal)
+	}
+	if f.Tool != "" {
+		where = append(where, "tool = ?")
+		args = append(args, f.Tool)
+	}
+	if !f.Since.IsZero() {
+		where = append(where, "called_at >= ?")
+		args = append(args, f.Since.UTC())
+	}
+	if !f.Until.IsZero() {
+		where = append(where, "called_at < ?")
+		args = append(args, f.Until.UTC())
+	}
+	if f.ErrorsOnly {
+		where = append(where, "error_code IS NOT NULL")
+	}
+	query := `SELECT call_id, called_at, principal, tool, arguments, result_bytes, error_code, error_message, latency_ms FROM tool_calls`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	limit := f.Limit
+	if limit <= 0 {
+		limit = DefaultToolCallLimit
+	}
+	query += " ORDER BY called_at DESC, call_id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var calls []ToolCall
+	for rows.Next() {
+		var c ToolCall
+		var code, msg sql.NullString
+		var latency int64
+		if err := rows.Scan(&c.CallID, &c.CalledAt, &c.Principal, &c.Tool, &c.Arguments, &c.ResultBytes, &code, &msg, &latency); err != nil {
+			return nil, err
+		}
+		c.ErrorCode, c.ErrorMessage = code.String, msg.String
+		c.Latency = time.Duration(latency) * time.Millisecond
+		calls = append(calls, c)
+	}
+	return calls, rows.Err()
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSQLRepository_ToolCalls(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	calls := []ToolCall{
+		{CalledAt: start, Principal: "github:alice", Tool: "search", Arguments: []byte(`{"query":"gpu"}`), ResultBytes: 120, Latency: 15 * time.Millisecond},
+		{CalledAt: start.Add(time.Minute), Principal: "github:bob", Tool: "calculate", ResultBytes: 0, ErrorCode: "-32029", ErrorMessage: "rate limited", Latency: time.Millisecond},
+		{CalledAt: start.Add(2 * time.Minute), Principal: "github:alice", Tool: "calculate", ResultBytes: 80, Latency: 40 * time.Millisecond},
+	}
+	for _, c := range calls {
+		if err := repo.InsertToolCall(ctx, c); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+
+	all, err := repo.ListToolCalls(ctx, ToolCallFilter{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(all) != 3 || all[0].Tool != "calculate" || all[0].Principal != "github:alice" {
+		t.Fatalf("list all = %+v", all)
+	}
+	if all[2].Latency != 15*time.Millisecond || string(all[2].Arguments) != `{"query":"gpu"}` {
+		t.Fatalf("oldest call = %+v", all[2])
+	}
+
+	errs, err := repo.ListToolCalls(ctx, ToolCallFilter{ErrorsOnly: true})
+	if err != nil {
+		t.Fatalf("list errors: %v", err)
+	}
+	if len(errs) != 1 || errs[0].ErrorCode != "-32029" || errs[0].ErrorMessage != "rate limited" {
+		t.Fatalf("errors = %+v", errs)
+	}
+
+	alice, err := repo.ListToolCalls(ctx, ToolCallFilter{Principal: "github:alice", Since: start.Add(time.Second), Limit: 10})
+	if err != nil {
+		t.Fatalf("list alice: %v", err)
+	}
+	if len(alice) != 1 || alice[0].Tool != "calculate" {
+		t.Fatalf("alice since = %+v", alice)
+	}
+
+	// 02:01+02:00 is 00:01 UTC: only the call at 00:00 is before it.
+	east := time.FixedZone("UTC+2", 2*60*60)
+	before, err := repo.ListToolCalls(ctx, ToolCallFilter{Until: time.Date(2025, 1, 1, 2, 1, 0, 0, east)})
+	if err != nil {
+		t.Fatalf("list until: %v", err)
+	}
+	if len(before) != 1 || before[0].Tool != "search" {
+		t.Fatalf("calls until 02:01+02:00 = %+v", before)
+	}
+}
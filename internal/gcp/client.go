@@ -7,11 +7,24 @@ import (
 	billing "cloud.google.com/go/billing/apiv1"
 	billingpb "cloud.google.com/go/billing/apiv1/billingpb"
 	gax "github.com/googleapis/gax-go/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.32.0"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/api/iterator"
 
 	"mcp-server/internal/database"
+	"mcp-server/internal/tracing"
 )
 
+var tracer = otel.Tracer("mcp-server/internal/gcp")
+
+// startSpan starts a client span covering a paginated Catalog API call.
+func startSpan(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, semconv.RPCSystemGRPC, semconv.RPCService("google.cloud.billing.v1.CloudCatalog"), semconv.RPCMethod(method))
+	return tracer.Start(ctx, "CloudCatalog/"+method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
 // ServiceIterator iterates over catalog services.
 type ServiceIterator interface {
 	Next() (*billingpb.Service, error)
@@ -31,16 +44,18 @@ type CloudCatalogClient interface {
 
 // Client wraps the Cloud Catalog API client.
 type Client struct {
-	catalog CloudCatalogClient
+	catalog  CloudCatalogClient
+	currency string
 }
 
-// NewClient creates a new Client.
-func NewClient(ctx context.Context) (*Client, error) {
+// NewClient creates a new Client returning prices in currency, an ISO 4217
+// code; empty selects USD.
+func NewClient(ctx context.Context, currency string) (*Client, error) {
 	c, err := billing.NewCloudCatalogClient(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return &Client{catalog: cloudCatalogClient{c}}, nil
+	return &Client{catalog: cloudCatalogClient{c}, currency: currency}, nil
 }
 
 type cloudCatalogClient struct {
@@ -61,7 +76,9 @@ func (c *Client) Close() error {
 }
 
 // ListServices retrieves all services from the Catalog API.
-func (c *Client) ListServices(ctx context.Context) ([]database.Service, error) {
+func (c *Client) ListServices(ctx context.Context) (_ []database.Service, err error) {
+	ctx, span := startSpan(ctx, "ListServices")
+	defer func() { tracing.End(span, err) }()
 	it := c.catalog.ListServices(ctx, &billingpb.ListServicesRequest{})
 	var services []database.Service
 	for {
@@ -83,8 +100,10 @@ func (c *Client) ListServices(ctx context.Context) ([]database.Service, error) {
 }
 
 // ListSkus retrieves all SKUs and pricing info for a service.
-func (c *Client) ListSkus(ctx context.Context, serviceID string) ([]database.SKU, []database.PricingInfo, error) {
-	it := c.catalog.ListSkus(ctx, &billingpb.ListSkusRequest{Parent: "services/" + serviceID})
+func (c *Client) ListSkus(ctx context.Context, serviceID string) (_ []database.SKU, _ []database.PricingInfo, err error) {
+	ctx, span := startSpan(ctx, "ListSkus", attribute.String("gcp.billing.service_id", serviceID))
+	defer func() { tracing.End(span, err) }()
+	it := c.catalog.ListSkus(ctx, &billingpb.ListSkusRequest{Parent: "services/" + serviceID, CurrencyCode: c.currency})
 	var skus []database.SKU
 	var prices []database.PricingInfo
 	for {
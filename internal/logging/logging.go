@@ -0,0 +1,133 @@
+// Package logging configures structured JSON logging for Cloud Logging and
+// carries correlation attributes, such as request and session IDs, in the
+// context so that every log line written with it includes them.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attribute keys shared by all binaries.
+const (
+	KeyRequestID = "request_id"
+	KeySessionID = "session_id"
+	KeyRunID     = "run_id"
+	KeyServiceID = "service_id"
+)
+
+// RequestIDHeader carries the request ID of an HTTP request.
+const RequestIDHeader = "X-Request-Id"
+
+// sessionIDHeader carries the MCP session ID of streamable HTTP requests.
+const sessionIDHeader = "Mcp-Session-Id"
+
+// New returns a logger writing JSON lines to w with the field names Cloud
+// Logging recognizes. level is "debug", "info", "warn" or "error"; anything
+// else selects info.
+func New(w io.Writer, level string) *slog.Logger {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		l = slog.LevelInfo
+	}
+	h := slog.NewJSONHandler(w, &slog.HandlerOptions{Level: l, ReplaceAttr: cloudLogging})
+	return slog.New(contextHandler{h})
+}
+
+// cloudLogging renames the level and message fields to "severity" and
+// "message" and spells warnings the way Cloud Logging does.
+func cloudLogging(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+	switch a.Key {
+	case slog.LevelKey:
+		a.Key = "severity"
+		if lvl, ok := a.Value.Any().(slog.Level); ok && lvl == slog.LevelWarn {
+			a.Value = slog.StringValue("WARNING")
+		}
+	case slog.MessageKey:
+		a.Key = "message"
+	}
+	return a
+}
+
+type attrsKey struct{}
+
+// With returns a copy of ctx whose log lines additionally carry attrs.
+func With(ctx context.Context, attrs ...slog.Attr) context.Context {
+	prev, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	merged := make([]slog.Attr, 0, len(prev)+len(attrs))
+	merged = append(merged, prev...)
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, attrsKey{}, merged)
+}
+
+// contextHandler adds the attributes stored by With and the active trace
+// and span IDs to every record logged with a context.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs, ok := ctx.Value(attrsKey{}).([]slog.Attr); ok {
+		r.AddAttrs(attrs...)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()), slog.String("span_id", sc.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// NewID returns a random 16-byte hex identifier for requests and runs.
+func NewID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Middleware assigns every request an ID, reusing a valid X-Request-Id
+// header, echoes it in the response and attaches it and the MCP session ID,
+// if any, to the request context. The ID is also set on the request header
+// so handlers that only see headers, such as MCP tool calls, can log it.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validID(id) {
+			id = NewID()
+			r.Header.Set(RequestIDHeader, id)
+		}
+		w.Header().Set(RequestIDHeader, id)
+		attrs := []slog.Attr{slog.String(KeyRequestID, id)}
+		if sid := r.Header.Get(sessionIDHeader); sid != "" {
+			attrs = append(attrs, slog.String(KeySessionID, sid))
+		}
+		next.ServeHTTP(w, r.WithContext(With(r.Context(), attrs...)))
+	})
+}
+
+// validID accepts client-supplied request IDs of up to 64 characters from
+// a conservative alphabet, so they cannot forge log fields.
+func validID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	return strings.IndexFunc(id, func(c rune) bool {
+		return !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.')
+	}) < 0
+}
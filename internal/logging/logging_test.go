@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func decode(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("decode %q: %v", buf.String(), err)
+	}
+	buf.Reset()
+	return line
+}
+
+func TestNew_CloudLoggingFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, "debug")
+	ctx := With(context.Background(), slog.String(KeyRunID, "r1"))
+	logger.WarnContext(ctx, "slow", "service_id", "svc")
+	line := decode(t, &buf)
+	if line["severity"] != "WARNING" || line["message"] != "slow" || line[KeyRunID] != "r1" || line["service_id"] != "svc" {
+		t.Fatalf("line = %v", line)
+	}
+}
+
+func TestNew_Level(t *testing.T) {
+	var buf bytes.Buffer
+	New(&buf, "warn").Info("hidden")
+	if buf.Len() != 0 {
+		t.Fatalf("info logged at warn level: %s", buf.String())
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, "info")
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.InfoContext(r.Context(), "handled")
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set(RequestIDHeader, "abc-123")
+	req.Header.Set("Mcp-Session-Id", "s1")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	line := decode(t, &buf)
+	if line[KeyRequestID] != "abc-123" || line[KeySessionID] != "s1" || rec.Header().Get(RequestIDHeader) != "abc-123" {
+		t.Fatalf("line = %v, header = %q", line, rec.Header().Get(RequestIDHeader))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, `forged" "severity":"ERROR`)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	line = decode(t, &buf)
+	id, _ := line[KeyRequestID].(string)
+	if len(id) != 32 || rec.Header().Get(RequestIDHeader) != id {
+		t.Fatalf("invalid client ID not replaced: line = %v", line)
+	}
+}
@@ -0,0 +1,269 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/database"
+)
+
+// redacted replaces the values of sensitive tool arguments in the audit log.
+const redacted = "[REDACTED]"
+
+// alwaysRedact lists the arguments redacted whatever the configuration: a
+// share token in the audit log would let its readers open shared estimates.
+var alwaysRedact = []string{"share_token"}
+
+// ToolCallStore persists and queries the tool call audit log.
+type ToolCallStore interface {
+	InsertToolCall(ctx context.Context, c database.ToolCall) error
+	ListToolCalls(ctx context.Context, f database.ToolCallFilter) ([]database.ToolCall, error)
+}
+
+var _ ToolCallStore = (*database.SQLRepository)(nil)
+
+// AuditLog records every tool call in the tool_calls table. Records are
+// written by a background goroutine so the database round trip does not add
+// to tool latency; Close flushes the records still buffered.
+type AuditLog struct {
+	store  ToolCallStore
+	redact map[string]bool
+	done   chan struct{}
+
+	mu     sync.RWMutex
+	calls  chan database.ToolCall
+	closed bool
+}
+
+// NewAuditLog creates an AuditLog that buffers up to buffer records and
+// replaces the values of arguments named in redact or alwaysRedact, at any
+// depth, with a placeholder.
+func NewAuditLog(store ToolCallStore, redact []string, buffer int) *AuditLog {
+	a := &AuditLog{
+		store:  store,
+		redact: make(map[string]bool),
+		calls:  make(chan database.ToolCall, buffer),
+		done:   make(chan struct{}),
+	}
+	for _, k := range slices.Concat(redact, alwaysRedact) {
+		a.redact[strings.ToLower(k)] = true
+	}
+	go a.run()
+	return a
+}
+
+func (a *AuditLog) run() {
+	defer close(a.done)
+	for c := range a.calls {
+		if err := a.store.InsertToolCall(context.Background(), c); err != nil {
+			slog.Error("write audit record", "tool", c.Tool, "principal", c.Principal, "error", err)
+		}
+	}
+}
+
+// Close stops accepting records and waits until buffered records are written
+// or ctx is done.
+func (a *AuditLog) Close(ctx context.Context) error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.calls)
+	}
+	a.mu.Unlock()
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("flush audit log: %w", ctx.Err())
+	}
+}
+
+// Middleware records principal, tool, redacted arguments, result size, error
+// code and latency of every tools/call request.
+func (a *AuditLog) Middleware(next sdk.MethodHandler) sdk.MethodHandler {
+	return func(ctx context.Context, method string, req sdk.Request) (sdk.Result, error) {
+		if method != "tools/call" {
+			return next(ctx, method, req)
+		}
+		start := time.Now()
+		res, err := next(ctx, method, req)
+		params := req.GetParams().(*sdk.CallToolParamsRaw)
+		p, _ := principalFromRequest(req)
+		c := database.ToolCall{
+			CalledAt:  start.UTC(),
+			Principal: p.String(),
+			Tool:      params.Name,
+			Arguments: a.redactArguments(params.Arguments),
+			Latency:   time.Since(start),
+		}
+		c.ErrorCode, c.ErrorMessage = errorCode(res, err)
+		if res != nil {
+			if b, merr := json.Marshal(res); merr == nil {
+				c.ResultBytes = len(b)
+			}
+		}
+		a.record(ctx, c)
+		return res, err
+	}
+}
+
+// record queues c for writing, dropping it if the buffer is full or the log is closed.
+func (a *AuditLog) record(ctx context.Context, c database.ToolCall) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		slog.WarnContext(ctx, "audit log closed, dropping record", "tool", c.Tool, "principal", c.Principal)
+		return
+	}
+	select {
+	case a.calls <- c:
+	default:
+		slog.WarnContext(ctx, "audit buffer full, dropping record", "tool", c.Tool, "principal", c.Principal)
+	}
+}
+
+// errorCode classifies the outcome of a tool call: JSON-RPC errors by their
+// numeric code, tool execution failures as "tool_error" and anything else
+// as "internal".
+func errorCode(res sdk.Result, err error) (code, message string) {
+	var rpcErr *jsonrpc.Error
+	switch {
+	case errors.As(err, &rpcErr):
+		return strconv.FormatInt(rpcErr.Code, 10), rpcErr.Message
+	case err != nil:
+		return "internal", err.Error()
+	}
+	if r, ok := res.(*sdk.CallToolResult); ok && r.IsError {
+		var msg string
+		for _, c := range r.Content {
+			if t, ok := c.(*sdk.TextContent); ok {
+				msg = t.Text
+				break
+			}
+		}
+		return "tool_error", msg
+	}
+	return "", ""
+}
+
+func (a *AuditLog) redactArguments(args json.RawMessage) []byte {
+	if len(args) == 0 || len(a.redact) == 0 {
+		return args
+	}
+	var v any
+	if err := json.Unmarshal(args, &v); err != nil {
+		return args
+	}
+	b, err := json.Marshal(a.redactValue(v))
+	if err != nil {
+		return args
+	}
+	return b
+}
+
+func (a *AuditLog) redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			if a.redact[strings.ToLower(k)] {
+				v[k] = redacted
+			} else {
+				v[k] = a.redactValue(val)
+			}
+		}
+	case []any:
+		for i, val := range v {
+			v[i] = a.redactValue(val)
+		}
+	}
+	return v
+}
+
+// QueryToolCallsInput filters the audit log.
+type QueryToolCallsInput struct {
+	Principal  string `json:"principal,omitempty" jsonschema:"principal such as github:octocat or apikey:ci"`
+	Tool       string `json:"tool,omitempty" jsonschema:"tool name"`
+	Since      string `json:"since,omitempty" jsonschema:"RFC 3339 timestamp; only calls at or after it"`
+	Until      string `json:"until,omitempty" jsonschema:"RFC 3339 timestamp; only calls before it"`
+	ErrorsOnly bool   `json:"errors_only,omitempty" jsonschema:"only return failed calls"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"maximum number of calls to return, default 100"`
+}
+
+// ToolCallRecord is one audit log entry.
+type ToolCallRecord struct {
+	CalledAt     string `json:"called_at"`
+	Principal    string `json:"principal"`
+	Tool         string `json:"tool"`
+	Arguments    any    `json:"arguments,omitempty"`
+	ResultBytes  int    `json:"result_bytes"`
+	ErrorCode    string `json:"error_code,omitempty"`
+	ErrorMessage string `json:"error_message,omitempty"`
+	LatencyMS    int64  `json:"latency_ms"`
+}
+
+// QueryToolCallsOutput lists audit log entries, newest first.
+type QueryToolCallsOutput struct {
+	Calls []ToolCallRecord `json:"calls"`
+}
+
+func queryToolCallsTool(store ToolCallStore) (*sdk.Tool, sdk.ToolHandlerFor[QueryToolCallsInput, QueryToolCallsOutput]) {
+	tool := &sdk.Tool{
+		Name:        "query_tool_calls",
+		Description: "Admin only. Lists recorded MCP tool calls with caller, redacted arguments, result size, error code and latency, newest first.",
+	}
+	handler := func(ctx context.Context, req *sdk.CallToolRequest, in QueryToolCallsInput) (*sdk.CallToolResult, QueryToolCallsOutput, error) {
+		f := database.ToolCallFilter{Principal: in.Principal, Tool: in.Tool, ErrorsOnly: in.ErrorsOnly, Limit: in.Limit}
+		var err error
+		if f.Since, err = parseTime("since", in.Since); err != nil {
+			return nil, QueryToolCallsOutput{}, err
+		}
+		if f.Until, err = parseTime("until", in.Until); err != nil {
+			return nil, QueryToolCallsOutput{}, err
+		}
+		calls, err := store.ListToolCalls(ctx, f)
+		if err != nil {
+			return nil, QueryToolCallsOutput{}, err
+		}
+		out := QueryToolCallsOutput{Calls: make([]ToolCallRecord, 0, len(calls))}
+		for _, c := range calls {
+			var args any
+			if len(c.Arguments) > 0 {
+				json.Unmarshal(c.Arguments, &args)
+			}
+			out.Calls = append(out.Calls, ToolCallRecord{
+				CalledAt:     c.CalledAt.UTC().Format(time.RFC3339Nano),
+				Principal:    c.Principal,
+				Tool:         c.Tool,
+				Arguments:    args,
+				ResultBytes:  c.ResultBytes,
+				ErrorCode:    c.ErrorCode,
+				ErrorMessage: c.ErrorMessage,
+				LatencyMS:    c.Latency.Milliseconds(),
+			})
+		}
+		return nil, out, nil
+	}
+	return tool, handler
+}
+
+func parseTime(field, s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: want an RFC 3339 timestamp such as 2025-01-02T15:04:05Z", field)
+	}
+	return t, nil
+}
@@ -0,0 +1,107 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/auth"
+	"mcp-server/internal/database"
+)
+
+type fakeToolCalls struct {
+	calls  []database.ToolCall
+	filter database.ToolCallFilter
+}
+
+func (f *fakeToolCalls) InsertToolCall(ctx context.Context, c database.ToolCall) error {
+	f.calls = append(f.calls, c)
+	return nil
+}
+
+func (f *fakeToolCalls) ListToolCalls(ctx context.Context, filter database.ToolCallFilter) ([]database.ToolCall, error) {
+	f.filter = filter
+	return f.calls, nil
+}
+
+func callRequest(t *testing.T, name, args string) *sdk.CallToolRequest {
+	t.Helper()
+	info, err := VerifyPrincipal(auth.WithPrincipal(context.Background(), auth.Principal{Login: "alice", Role: auth.RoleUser}), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &sdk.CallToolRequest{
+		Params: &sdk.CallToolParamsRaw{Name: name, Arguments: json.RawMessage(args)},
+		Extra:  &sdk.RequestExtra{TokenInfo: info},
+	}
+}
+
+func TestAuditLog_Middleware(t *testing.T) {
+	store := &fakeToolCalls{}
+	a := NewAuditLog(store, []string{"note"}, 10)
+	h := a.Middleware(func(ctx context.Context, method string, req sdk.Request) (sdk.Result, error) {
+		if req.GetParams().(*sdk.CallToolParamsRaw).Name == "denied" {
+			return nil, &jsonrpc.Error{Code: codeAccessDenied, Message: "denied"}
+		}
+		return &sdk.CallToolResult{Content: []sdk.Content{&sdk.TextContent{Text: "ok"}}}, nil
+	})
+	ctx := context.Background()
+	if _, err := h(ctx, "tools/call", callRequest(t, "load_estimate", `{"id":"e1","note":"n","share_token":"secret","nested":{"Share_Token":"x"}}`)); err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	if _, err := h(ctx, "tools/call", callRequest(t, "denied", `{}`)); err == nil {
+		t.Fatal("expected denied call to fail")
+	}
+	if err := a.Close(ctx); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	if len(store.calls) != 2 {
+		t.Fatalf("recorded %d calls, want 2", len(store.calls))
+	}
+	ok := store.calls[0]
+	if ok.Principal != "github:alice" || ok.Tool != "load_estimate" || ok.ResultBytes == 0 || ok.ErrorCode != "" {
+		t.Fatalf("successful call = %+v", ok)
+	}
+	var args map[string]any
+	if err := json.Unmarshal(ok.Arguments, &args); err != nil {
+		t.Fatalf("arguments: %v", err)
+	}
+	if args["id"] != "e1" || args["note"] != redacted || args["share_token"] != redacted || args["nested"].(map[string]any)["Share_Token"] != redacted {
+		t.Fatalf("arguments not redacted: %s", ok.Arguments)
+	}
+	if denied := store.calls[1]; denied.ErrorCode != "-32003" || denied.ErrorMessage != "denied" {
+		t.Fatalf("denied call = %+v", denied)
+	}
+}
+
+func TestErrorCode_ToolError(t *testing.T) {
+	var res sdk.CallToolResult
+	res.SetError(errors.New("sku not found"))
+	code, msg := errorCode(&res, nil)
+	if code != "tool_error" || msg != "sku not found" {
+		t.Fatalf("errorCode = %q, %q", code, msg)
+	}
+}
+
+func TestQueryToolCallsTool(t *testing.T) {
+	store := &fakeToolCalls{calls: []database.ToolCall{{Principal: "apikey:ci", Tool: "search", Arguments: []byte(`{"query":"gpu"}`)}}}
+	_, handler := queryToolCallsTool(store)
+	_, out, err := handler(context.Background(), nil, QueryToolCallsInput{Tool: "search", Since: "2025-01-01T00:00:00Z"})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if store.filter.Tool != "search" || store.filter.Since.IsZero() {
+		t.Fatalf("filter = %+v", store.filter)
+	}
+	if len(out.Calls) != 1 || out.Calls[0].Principal != "apikey:ci" {
+		t.Fatalf("output = %+v", out)
+	}
+	if _, _, err := handler(context.Background(), nil, QueryToolCallsInput{Since: "yesterday"}); err == nil {
+		t.Fatal("expected error for invalid since")
+	}
+}
@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	sdkauth "github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/auth"
+)
+
+// codeAccessDenied is the JSON-RPC error code returned when the caller lacks
+// the role required by a tool.
+const codeAccessDenied = -32003
+
+const principalExtraKey = "principal"
+
+// VerifyPrincipal is a go-sdk TokenVerifier that hands the principal set by
+// auth.Authenticator.Middleware to the MCP session. The token itself has
+// already been verified, so the returned TokenInfo only lives for the request.
+func VerifyPrincipal(ctx context.Context, token string, req *http.Request) (*sdkauth.TokenInfo, error) {
+	p, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, sdkauth.ErrInvalidToken
+	}
+	return &sdkauth.TokenInfo{
+		UserID:     p.String(),
+		Expiration: time.Now().Add(time.Minute),
+		Extra:      map[string]any{principalExtraKey: p},
+	}, nil
+}
+
+// principalFromRequest returns the principal attached to an incoming request by VerifyPrincipal.
+func principalFromRequest(req sdk.Request) (auth.Principal, bool) {
+	extra := req.GetExtra()
+	if extra == nil || extra.TokenInfo == nil {
+		return auth.Principal{}, false
+	}
+	p, ok := extra.TokenInfo.Extra[principalExtraKey].(auth.Principal)
+	return p, ok
+}
+
+// ToolRoles maps tool names to the minimum role required to call them.
+// Tools without an entry require auth.RoleUser.
+type ToolRoles map[string]auth.Role
+
+func (r ToolRoles) required(tool string) auth.Role {
+	if role, ok := r[tool]; ok {
+		return role
+	}
+	return auth.RoleUser
+}
+
+// Middleware rejects tools/call requests from principals without the required
+// role and hides those tools from tools/list.
+func (r ToolRoles) Middleware(next sdk.MethodHandler) sdk.MethodHandler {
+	return func(ctx context.Context, method string, req sdk.Request) (sdk.Result, error) {
+		switch method {
+		case "tools/call":
+			name := req.GetParams().(*sdk.CallToolParamsRaw).Name
+			p, _ := principalFromRequest(req)
+			if need := r.required(name); !p.Role.Satisfies(need) {
+				data, _ := json.Marshal(map[string]string{"tool": name, "required_role": string(need)})
+				return nil, &jsonrpc.Error{
+					Code:    codeAccessDenied,
+					Message: fmt.Sprintf("tool %q requires the %s role", name, need),
+					Data:    data,
+				}
+			}
+		case "tools/list":
+			res, err := next(ctx, method, req)
+			if err != nil {
+				return res, err
+			}
+			p, _ := principalFromRequest(req)
+			list := res.(*sdk.ListToolsResult)
+			visible := list.Tools[:0:0]
+			for _, t := range list.Tools {
+				if p.Role.Satisfies(r.required(t.Name)) {
+					visible = append(visible, t)
+				}
+			}
+			list.Tools = visible
+			return list, nil
+		}
+		return next(ctx, method, req)
+	}
+}
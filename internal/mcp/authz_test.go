@@ -0,0 +1,44 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdkauth "github.com/modelcontextprotocol/go-sdk/auth"
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/auth"
+)
+
+func TestVerifyPrincipal(t *testing.T) {
+	if _, err := VerifyPrincipal(context.Background(), "token", nil); !errors.Is(err, sdkauth.ErrInvalidToken) {
+		t.Fatalf("without principal: got %v, want ErrInvalidToken", err)
+	}
+	want := auth.Principal{Login: "octocat", Role: auth.RoleAdmin}
+	info, err := VerifyPrincipal(auth.WithPrincipal(context.Background(), want), "token", nil)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if info.UserID != "github:octocat" {
+		t.Fatalf("user id = %q", info.UserID)
+	}
+	req := &sdk.CallToolRequest{Extra: &sdk.RequestExtra{TokenInfo: info}}
+	got, ok := principalFromRequest(req)
+	if !ok || got != want {
+		t.Fatalf("principal = %+v, %v; want %+v", got, ok, want)
+	}
+}
+
+func TestToolRoles_DeniesWithoutPrincipal(t *testing.T) {
+	called := false
+	next := func(ctx context.Context, method string, req sdk.Request) (sdk.Result, error) {
+		called = true
+		return &sdk.CallToolResult{}, nil
+	}
+	h := ToolRoles{}.Middleware(next)
+	req := &sdk.CallToolRequest{Params: &sdk.CallToolParamsRaw{Name: "search"}}
+	if _, err := h(context.Background(), "tools/call", req); err == nil || called {
+		t.Fatalf("unauthenticated call: err %v, called %v; want denied", err, called)
+	}
+}
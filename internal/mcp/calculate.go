@@ -0,0 +1,233 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/compute"
+	"mcp-server/internal/database"
+	"mcp-server/internal/pricing"
+	"mcp-server/internal/units"
+)
+
+// CalculateInput prices a quantity of one SKU.
+type CalculateInput struct {
+	SKUID    string  `json:"sku_id" jsonschema:"SKU ID such as 6F81-5844-456A"`
+	Quantity float64 `json:"quantity" jsonschema:"usage in unit, such as 730 for 730 hours"`
+	// Unit, when set, is converted to the SKU's usage unit, so storage can be
+	// given in TB or hours in months.
+	Unit   string `json:"unit,omitempty" jsonschema:"unit of quantity, such as h, mo, GB, TiB or GB.mo; default the SKU's usage unit"`
+	Region string `json:"region,omitempty" jsonschema:"region the usage runs in; must be one the SKU is offered in"`
+	// HoursPerMonth sets the sustained use discount of eligible Compute
+	// Engine SKUs, which grows with the share of the month a resource runs.
+	// The quantity alone does not say how long each resource runs, so no
+	// discount is applied without it.
+	HoursPerMonth float64 `json:"hours_per_month,omitempty" jsonschema:"hours per month each resource runs, for sustained use discounts, such as 730 for a full month; no discount when unset"`
+	// MonthHours is the length of a month, both when converting between
+	// hourly and monthly units and for the share of the month
+	// HoursPerMonth is.
+	MonthHours float64 `json:"month_hours,omitempty" jsonschema:"hours in a month, for converting units such as h and mo and for sustained use discounts; default 730"`
+	// IgnoreFreeTier prices the quantity as if the billing account had
+	// already used its free allowance elsewhere.
+	IgnoreFreeTier bool `json:"ignore_free_tier,omitempty" jsonschema:"do not deduct the monthly free allowance of the SKU, for accounts that use it elsewhere"`
+	// freeTierUsed is the part of the free allowance already used by other
+	// lines of an estimate.
+	freeTierUsed float64
+}
+
+// TierLine is the part of the quantity billed at one tier.
+type TierLine struct {
+	StartUsageAmount float64 `json:"start_usage_amount"`
+	Quantity         float64 `json:"quantity"`
+	UnitPrice        float64 `json:"unit_price"`
+	Cost             float64 `json:"cost"`
+}
+
+// Discount is an amount taken off the cost of the tiers.
+type Discount struct {
+	Kind        string  `json:"kind"`
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+}
+
+// FreeTier describes the monthly free allowance of a SKU and how much of the
+// quantity it covers.
+type FreeTier struct {
+	Note      string  `json:"note"`
+	Allowance float64 `json:"allowance"`
+	Covered   float64 `json:"covered"`
+	Amount    float64 `json:"amount"`
+	Applied   bool    `json:"applied"`
+}
+
+// CalculateOutput is the cost of the quantity, itemized by tier, less
+// discounts.
+type CalculateOutput struct {
+	SKUID       string  `json:"sku_id"`
+	Description string  `json:"description"`
+	Service     string  `json:"service,omitempty"`
+	Region      string  `json:"region,omitempty"`
+	UsageUnit   string  `json:"usage_unit"`
+	Quantity    float64 `json:"quantity"`
+	// InputQuantity and InputUnit are the quantity as given, when it was
+	// converted to the usage unit.
+	InputQuantity float64    `json:"input_quantity,omitempty"`
+	InputUnit     string     `json:"input_unit,omitempty"`
+	Currency      string     `json:"currency"`
+	Tiers         []TierLine `json:"tiers"`
+	Subtotal      float64    `json:"subtotal"`
+	Discounts     []Discount `json:"discounts,omitempty"`
+	FreeTier      *FreeTier  `json:"free_tier,omitempty"`
+	Total         float64    `json:"total"`
+}
+
+func calculateTool(skus SKUStore, catalog CatalogStore) (*sdk.Tool, sdk.ToolHandlerFor[CalculateInput, CalculateOutput]) {
+	tool := &sdk.Tool{
+		Name: "calculate",
+		Description: "Calculates the cost of a usage quantity of a Google Cloud SKU, in its usage unit or a compatible one such as TB for GiBy, from its current tiered pricing, " +
+			"less free tier allowances and the sustained use discount of eligible Compute Engine SKUs.",
+	}
+	handler := func(ctx context.Context, req *sdk.CallToolRequest, in CalculateInput) (*sdk.CallToolResult, CalculateOutput, error) {
+		if err := validateRegion(ctx, catalog, in.Region); err != nil {
+			return nil, CalculateOutput{}, err
+		}
+		out, err := calculate(ctx, skus, in)
+		if err != nil {
+			return nil, CalculateOutput{}, err
+		}
+		return nil, out, nil
+	}
+	return tool, handler
+}
+
+// calculate prices in against the current pricing of its SKU, less the free
+// tier allowance and sustained use discount it earns. The region, if any,
+// must already be validated.
+func calculate(ctx context.Context, skus SKUStore, in CalculateInput) (CalculateOutput, error) {
+	if in.SKUID == "" {
+		return CalculateOutput{}, errors.New("sku_id is required")
+	}
+	monthHours := in.MonthHours
+	if monthHours == 0 {
+		monthHours = compute.HoursPerMonth
+	}
+	if monthHours < 0 || monthHours > 744 {
+		return CalculateOutput{}, fmt.Errorf("month_hours %v must be between 0 and 744", monthHours)
+	}
+	hours := in.HoursPerMonth
+	if hours < 0 || hours > monthHours {
+		return CalculateOutput{}, fmt.Errorf("hours_per_month %v must be between 0 and month_hours %v", hours, monthHours)
+	}
+	sku, err := skus.SKUByID(ctx, in.SKUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return CalculateOutput{}, fmt.Errorf("SKU %q not found", in.SKUID)
+	}
+	if err != nil {
+		return CalculateOutput{}, err
+	}
+	if in.Region != "" && len(sku.ServiceRegions) > 0 && !slices.Contains(sku.ServiceRegions, in.Region) {
+		return CalculateOutput{}, fmt.Errorf("SKU %q is not offered in %s; it is offered in %v", in.SKUID, in.Region, sku.ServiceRegions)
+	}
+	p, err := skus.LatestPricingInfo(ctx, in.SKUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return CalculateOutput{}, fmt.Errorf("SKU %q has no pricing", in.SKUID)
+	}
+	if err != nil {
+		return CalculateOutput{}, err
+	}
+	quantity, err := convertQuantity(in.Quantity, in.Unit, p.UsageUnit, monthHours)
+	if err != nil {
+		return CalculateOutput{}, fmt.Errorf("SKU %q: %w", in.SKUID, err)
+	}
+	cost, err := pricing.Calculate(p, quantity)
+	if err != nil {
+		return CalculateOutput{}, err
+	}
+	out := CalculateOutput{
+		SKUID:       sku.SKUID,
+		Description: sku.Description,
+		Service:     sku.Category.ServiceDisplayName,
+		Region:      in.Region,
+		UsageUnit:   cost.UsageUnit,
+		Quantity:    cost.Quantity,
+		Currency:    cost.Currency,
+		Tiers:       make([]TierLine, 0, len(cost.Tiers)),
+		Subtotal:    cost.Total,
+		Total:       cost.Total,
+	}
+	if in.Unit != "" && in.Unit != p.UsageUnit {
+		out.InputQuantity, out.InputUnit = in.Quantity, in.Unit
+	}
+	for _, t := range cost.Tiers {
+		out.Tiers = append(out.Tiers, TierLine(t))
+	}
+	if a, ok := pricing.FreeAllowance(sku, p.UsageUnit, in.Region); ok {
+		left := a
+		left.Quantity = max(0, a.Quantity-in.freeTierUsed)
+		covered, amount, err := pricing.FreeTierValue(p, left, quantity)
+		if err != nil {
+			return CalculateOutput{}, err
+		}
+		out.FreeTier = &FreeTier{Note: a.Note, Allowance: a.Quantity, Covered: covered, Amount: amount, Applied: !in.IgnoreFreeTier}
+		if !in.IgnoreFreeTier && amount > 0 {
+			out.Discounts = append(out.Discounts, Discount{Kind: "free_tier", Description: a.Note, Amount: amount})
+			out.Total -= amount
+		}
+	}
+	if hours == 0 {
+		return out, nil
+	}
+	if d, ok := sustainedUseDiscount(sku, cost, hours, monthHours); ok {
+		out.Discounts = append(out.Discounts, d)
+		out.Total -= d.Amount
+	}
+	return out, nil
+}
+
+// convertQuantity converts quantity in unit to usageUnit with months of
+// monthHours hours. An empty unit is the usage unit.
+func convertQuantity(quantity float64, unit, usageUnit string, monthHours float64) (float64, error) {
+	if unit == "" || unit == usageUnit {
+		return quantity, nil
+	}
+	from, err := units.Parse(unit)
+	if err != nil {
+		return 0, err
+	}
+	to, err := units.Parse(usageUnit)
+	if err != nil {
+		return 0, fmt.Errorf("usage unit %q cannot be converted to; give the quantity in it", usageUnit)
+	}
+	q, err := units.Converter{HoursPerMonth: monthHours}.Convert(quantity, from, to)
+	if errors.Is(err, units.ErrIncompatible) {
+		return 0, fmt.Errorf("unit %s measures %s but the usage unit %s measures %s; give the quantity in %s or a compatible unit",
+			unit, from.Dimension(), usageUnit, to.Dimension(), usageUnit)
+	}
+	return q, err
+}
+
+// sustainedUseDiscount returns the sustained use discount on cost of an
+// eligible hourly SKU for resources running hours of a monthHours-hour month.
+func sustainedUseDiscount(sku database.SKU, cost pricing.Cost, hours, monthHours float64) (Discount, bool) {
+	if cost.UsageUnit != "h" && cost.UsageUnit != "GiBy.h" {
+		return Discount{}, false
+	}
+	family, tiers, ok := compute.SustainedUseTiers(sku)
+	if !ok {
+		return Discount{}, false
+	}
+	amount := cost.Total * (1 - pricing.SustainedUseMultiplier(tiers, hours/monthHours))
+	if amount <= 0 {
+		return Discount{}, false
+	}
+	return Discount{
+		Kind:        "sustained_use",
+		Description: fmt.Sprintf("%s sustained use discount for resources running %.0f of %.0f hours", family, hours, monthHours),
+		Amount:      amount,
+	}, true
+}
@@ -0,0 +1,167 @@
+package mcp
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"mcp-server/internal/database"
+)
+
+func TestCalculateTool(t *testing.T) {
+	skus := fakeSKUs{
+		skus: map[string]database.SKU{
+			"egress": {SKUID: "egress", Description: "Network egress", ServiceRegions: []string{"us-central1"}},
+		},
+		prices: map[string]database.PricingInfo{
+			"egress": {SKUID: "egress", CurrencyCode: "USD", UsageUnit: "GiBy", TieredRates: []database.TieredRate{
+				{StartUsageAmount: 0, UnitPrice: database.Money{}},
+				{StartUsageAmount: 1, UnitPrice: database.Money{Nanos: 120_000_000}},
+			}},
+		},
+	}
+	_, handler := calculateTool(skus, &fakeCatalog{regions: testRegions})
+	ctx := context.Background()
+
+	_, out, err := handler(ctx, nil, CalculateInput{SKUID: "egress", Quantity: 11, Region: "us-central1"})
+	if err != nil {
+		t.Fatalf("calculate: %v", err)
+	}
+	if len(out.Tiers) != 2 || out.Total < 1.199 || out.Total > 1.201 || out.UsageUnit != "GiBy" {
+		t.Fatalf("output = %+v", out)
+	}
+
+	for _, tt := range []struct {
+		in   CalculateInput
+		want string
+	}{
+		{CalculateInput{SKUID: "egress", Quantity: 1, Region: "europe-west1"}, "not offered in europe-west1"},
+		{CalculateInput{SKUID: "egress", Quantity: 1, Region: "moon-1"}, "unknown region"},
+		{CalculateInput{SKUID: "missing", Quantity: 1}, "not found"},
+		{CalculateInput{SKUID: "egress", Quantity: -1}, "non-negative"},
+	} {
+		if _, _, err := handler(ctx, nil, tt.in); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("calculate %+v: got %v, want error containing %q", tt.in, err, tt.want)
+		}
+	}
+}
+
+func TestCalculateTool_Units(t *testing.T) {
+	skus := fakeSKUs{
+		skus: map[string]database.SKU{"storage": {SKUID: "storage", Description: "Standard Storage"}},
+		prices: map[string]database.PricingInfo{
+			"storage": {SKUID: "storage", CurrencyCode: "USD", UsageUnit: "GiBy.mo", TieredRates: []database.TieredRate{{UnitPrice: database.Money{Nanos: 20_000_000}}}},
+		},
+	}
+	_, handler := calculateTool(skus, &fakeCatalog{regions: testRegions})
+	ctx := context.Background()
+
+	_, out, err := handler(ctx, nil, CalculateInput{SKUID: "storage", Quantity: 2, Unit: "TiB.mo"})
+	if err != nil {
+		t.Fatalf("calculate in TiB.mo: %v", err)
+	}
+	if out.Quantity != 2048 || out.InputQuantity != 2 || out.InputUnit != "TiB.mo" || math.Abs(out.Total-40.96) > 1e-9 {
+		t.Fatalf("output = %+v", out)
+	}
+	// 1 GiB stored for 365 hours is half a GiB month.
+	if _, out, err := handler(ctx, nil, CalculateInput{SKUID: "storage", Quantity: 365, Unit: "GiBy.h"}); err != nil || out.Quantity != 0.5 {
+		t.Fatalf("output in GiBy.h = %+v, %v", out, err)
+	}
+	// In a 720-hour month, 360 hours are half a month too.
+	if _, out, err := handler(ctx, nil, CalculateInput{SKUID: "storage", Quantity: 360, Unit: "GiBy.h", MonthHours: 720}); err != nil || out.Quantity != 0.5 {
+		t.Fatalf("output in GiBy.h with 720-hour months = %+v, %v", out, err)
+	}
+	if _, _, err := handler(ctx, nil, CalculateInput{SKUID: "storage", Quantity: 1, Unit: "GiBy.h", MonthHours: -1}); err == nil || !strings.Contains(err.Error(), "month_hours") {
+		t.Fatalf("negative month_hours: got %v", err)
+	}
+	if _, out, err := handler(ctx, nil, CalculateInput{SKUID: "storage", Quantity: 3, Unit: "GiBy.mo"}); err != nil || out.InputUnit != "" {
+		t.Fatalf("output in the usage unit = %+v, %v", out, err)
+	}
+
+	for _, tt := range []struct {
+		unit string
+		want string
+	}{
+		{"h", "unit h measures time but the usage unit GiBy.mo measures data × time"},
+		{"GB", "give the quantity in GiBy.mo"},
+		{"parsecs", `unknown unit "parsecs"`},
+	} {
+		if _, _, err := handler(ctx, nil, CalculateInput{SKUID: "storage", Quantity: 1, Unit: tt.unit}); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("calculate in %s: got %v, want error containing %q", tt.unit, err, tt.want)
+		}
+	}
+}
+
+func TestCalculateTool_SustainedUse(t *testing.T) {
+	skus := fakeSKUs{
+		skus: map[string]database.SKU{
+			"n1": {SKUID: "n1", Description: "N1 Predefined Instance Core running in Americas",
+				Category: database.Category{ResourceFamily: "Compute", ResourceGroup: "N1Standard", UsageType: "OnDemand"}},
+		},
+		prices: map[string]database.PricingInfo{"n1": price("n1", 30_000_000)},
+	}
+	_, handler := calculateTool(skus, &fakeCatalog{regions: testRegions})
+	ctx := context.Background()
+
+	// Two vCPUs for a full month earn the full 30% N1 discount.
+	_, out, err := handler(ctx, nil, CalculateInput{SKUID: "n1", Quantity: 1460, HoursPerMonth: 730})
+	if err != nil {
+		t.Fatalf("calculate: %v", err)
+	}
+	if len(out.Discounts) != 1 || out.Discounts[0].Kind != "sustained_use" || math.Abs(out.Subtotal-43.8) > 1e-9 || math.Abs(out.Total-30.66) > 1e-9 {
+		t.Fatalf("output = %+v", out)
+	}
+
+	// Running half the month earns 10%.
+	_, out, err = handler(ctx, nil, CalculateInput{SKUID: "n1", Quantity: 730, HoursPerMonth: 365})
+	if err != nil || math.Abs(out.Total-19.71) > 1e-9 {
+		t.Fatalf("half month output = %+v, %v", out, err)
+	}
+
+	// Ten hours of use earn nothing, and without hours_per_month the
+	// running time of each resource is unknown.
+	_, out, err = handler(ctx, nil, CalculateInput{SKUID: "n1", Quantity: 10, Unit: "h"})
+	if err != nil || len(out.Discounts) != 0 || math.Abs(out.Total-0.3) > 1e-9 {
+		t.Fatalf("10 hour output = %+v, %v", out, err)
+	}
+
+	// In a 720-hour month, 720 hours are the full month too.
+	_, out, err = handler(ctx, nil, CalculateInput{SKUID: "n1", Quantity: 720, HoursPerMonth: 720, MonthHours: 720})
+	if err != nil || len(out.Discounts) != 1 || math.Abs(out.Total-15.12) > 1e-9 || !strings.Contains(out.Discounts[0].Description, "720 of 720 hours") {
+		t.Fatalf("720-hour month output = %+v, %v", out, err)
+	}
+
+	if _, _, err := handler(ctx, nil, CalculateInput{SKUID: "n1", Quantity: 1, HoursPerMonth: 800}); err == nil || !strings.Contains(err.Error(), "hours_per_month") {
+		t.Fatalf("hours_per_month 800: got %v", err)
+	}
+	if _, _, err := handler(ctx, nil, CalculateInput{SKUID: "n1", Quantity: 1, HoursPerMonth: 730, MonthHours: 720}); err == nil || !strings.Contains(err.Error(), "month_hours 720") {
+		t.Fatalf("hours_per_month beyond month_hours: got %v", err)
+	}
+}
+
+func TestCalculateTool_FreeTier(t *testing.T) {
+	skus := fakeSKUs{
+		skus: map[string]database.SKU{
+			"run": {SKUID: "run", ServiceID: "152E-C115-5142", Description: "CPU Allocation Time"},
+		},
+		prices: map[string]database.PricingInfo{
+			"run": {SKUID: "run", CurrencyCode: "USD", UsageUnit: "s", TieredRates: []database.TieredRate{{UnitPrice: database.Money{Nanos: 24_000}}}},
+		},
+	}
+	_, handler := calculateTool(skus, &fakeCatalog{regions: testRegions})
+	ctx := context.Background()
+
+	_, out, err := handler(ctx, nil, CalculateInput{SKUID: "run", Quantity: 200000})
+	if err != nil {
+		t.Fatalf("calculate: %v", err)
+	}
+	if out.FreeTier == nil || !out.FreeTier.Applied || out.FreeTier.Covered != 180000 || len(out.Discounts) != 1 || math.Abs(out.Total-0.48) > 1e-9 {
+		t.Fatalf("output = %+v", out)
+	}
+
+	_, out, err = handler(ctx, nil, CalculateInput{SKUID: "run", Quantity: 200000, IgnoreFreeTier: true})
+	if err != nil || out.FreeTier == nil || out.FreeTier.Applied || len(out.Discounts) != 0 || math.Abs(out.Total-4.8) > 1e-9 {
+		t.Fatalf("ignored free tier output = %+v, %v", out, err)
+	}
+}
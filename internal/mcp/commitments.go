@@ -0,0 +1,281 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/compute"
+	"mcp-server/internal/database"
+	"mcp-server/internal/pricing"
+)
+
+// commitmentTerms are the committed use terms compared against on-demand,
+// by the usage type of their SKUs.
+var commitmentTerms = []struct {
+	usageType, name string
+	months          int
+}{
+	{"Commit1Yr", "1-year", 12},
+	{"Commit3Yr", "3-year", 36},
+}
+
+// CompareCommitmentsInput describes the resources a commitment would cover.
+type CompareCommitmentsInput struct {
+	SKUID       string  `json:"sku_id" jsonschema:"on-demand SKU of the resource, such as an N2 Instance Core SKU"`
+	Quantity    float64 `json:"quantity" jsonschema:"resources committed to, in the SKU's unit without the hours, such as 16 vCPUs or 64 GiB"`
+	Utilization float64 `json:"utilization,omitempty" jsonschema:"expected share of the month the resources run, from 0 to 1; default 1"`
+	Region      string  `json:"region,omitempty" jsonschema:"region of the commitment; required when the SKU is offered in several"`
+	MonthHours  float64 `json:"month_hours,omitempty" jsonschema:"hours in a month, default 730"`
+}
+
+// CommitmentTerm compares one commitment term with on-demand usage.
+type CommitmentTerm struct {
+	Term        string  `json:"term"`
+	SKUID       string  `json:"sku_id"`
+	Description string  `json:"description"`
+	UnitPrice   float64 `json:"unit_price"`
+	MonthlyCost float64 `json:"monthly_cost"`
+	// MonthlySavings is negative when on-demand is cheaper at the expected
+	// utilization.
+	MonthlySavings float64 `json:"monthly_savings"`
+	TotalSavings   float64 `json:"total_savings"`
+	SavingsPercent float64 `json:"savings_percent"`
+	// BreakevenUtilization is the utilization above which the commitment is
+	// cheaper than on-demand, or absent when it never is.
+	BreakevenUtilization *float64 `json:"breakeven_utilization,omitempty"`
+}
+
+// CompareCommitmentsOutput compares on-demand usage with each commitment term
+// available for the resource.
+type CompareCommitmentsOutput struct {
+	Region          string           `json:"region"`
+	Currency        string           `json:"currency"`
+	UsageUnit       string           `json:"usage_unit"`
+	Quantity        float64          `json:"quantity"`
+	Utilization     float64          `json:"utilization"`
+	OnDemandSKUID   string           `json:"on_demand_sku_id"`
+	OnDemandMonthly float64          `json:"on_demand_monthly_cost"`
+	Commitments     []CommitmentTerm `json:"commitments"`
+	Unavailable     []string         `json:"unavailable_terms,omitempty"`
+	Cheapest        string           `json:"cheapest"`
+}
+
+func compareCommitmentsTool(skus SKUStore, catalog CatalogStore) (*sdk.Tool, sdk.ToolHandlerFor[CompareCommitmentsInput, CompareCommitmentsOutput]) {
+	tool := &sdk.Tool{
+		Name: "compare_commitments",
+		Description: "Compares the monthly cost of on-demand usage of a resource with 1-year and 3-year committed use discounts " +
+			"at an expected utilization, with the breakeven utilization and savings over each term.",
+	}
+	handler := func(ctx context.Context, req *sdk.CallToolRequest, in CompareCommitmentsInput) (*sdk.CallToolResult, CompareCommitmentsOutput, error) {
+		if in.SKUID == "" {
+			return nil, CompareCommitmentsOutput{}, errors.New("sku_id is required")
+		}
+		if in.Quantity <= 0 {
+			return nil, CompareCommitmentsOutput{}, errors.New("quantity must be positive")
+		}
+		utilization := in.Utilization
+		if utilization == 0 {
+			utilization = 1
+		}
+		if utilization < 0 || utilization > 1 {
+			return nil, CompareCommitmentsOutput{}, fmt.Errorf("utilization %v must be between 0 and 1", utilization)
+		}
+		hours := in.MonthHours
+		if hours == 0 {
+			hours = compute.HoursPerMonth
+		}
+		if hours < 0 || hours > 744 {
+			return nil, CompareCommitmentsOutput{}, fmt.Errorf("month_hours %v must be between 0 and 744", hours)
+		}
+		if err := validateRegion(ctx, catalog, in.Region); err != nil {
+			return nil, CompareCommitmentsOutput{}, err
+		}
+
+		sku, p, err := skuPricing(ctx, skus, in.SKUID)
+		if err != nil {
+			return nil, CompareCommitmentsOutput{}, err
+		}
+		if strings.HasPrefix(sku.Category.UsageType, "Commit") {
+			return nil, CompareCommitmentsOutput{}, fmt.Errorf("SKU %q is a commitment SKU; pass the on-demand SKU of the resource", in.SKUID)
+		}
+		if p.UsageUnit != "h" && p.UsageUnit != "GiBy.h" {
+			return nil, CompareCommitmentsOutput{}, fmt.Errorf("SKU %q is priced per %s; commitments cover hourly resources", in.SKUID, p.UsageUnit)
+		}
+		region := in.Region
+		switch {
+		case region != "" && len(sku.ServiceRegions) > 0 && !slices.Contains(sku.ServiceRegions, region):
+			return nil, CompareCommitmentsOutput{}, fmt.Errorf("SKU %q is not offered in %s; it is offered in %v", in.SKUID, region, sku.ServiceRegions)
+		case region == "" && len(sku.ServiceRegions) == 1:
+			region = sku.ServiceRegions[0]
+		case region == "":
+			return nil, CompareCommitmentsOutput{}, fmt.Errorf("SKU %q is offered in several regions; set region", in.SKUID)
+		}
+
+		// onDemand is the monthly cost of running the resources for share u
+		// of the month, less the sustained use discount it earns.
+		onDemand := func(u float64) (float64, error) {
+			cost, err := pricing.Calculate(p, in.Quantity*hours*u)
+			if err != nil {
+				return 0, err
+			}
+			total := cost.Total
+			if d, ok := sustainedUseDiscount(sku, cost, hours*u, hours); ok {
+				total -= d.Amount
+			}
+			return total, nil
+		}
+		onDemandMonthly, err := onDemand(utilization)
+		if err != nil {
+			return nil, CompareCommitmentsOutput{}, err
+		}
+		out := CompareCommitmentsOutput{
+			Region:          region,
+			Currency:        p.CurrencyCode,
+			UsageUnit:       p.UsageUnit,
+			Quantity:        in.Quantity,
+			Utilization:     utilization,
+			OnDemandSKUID:   sku.SKUID,
+			OnDemandMonthly: onDemandMonthly,
+			Commitments:     []CommitmentTerm{},
+			Cheapest:        "on-demand",
+		}
+		cheapest := onDemandMonthly
+		for _, term := range commitmentTerms {
+			commit, err := commitmentSKU(ctx, catalog, sku, term.usageType, region)
+			if err != nil {
+				return nil, CompareCommitmentsOutput{}, err
+			}
+			if commit.SKUID == "" {
+				out.Unavailable = append(out.Unavailable, term.name)
+				continue
+			}
+			cp, err := skus.LatestPricingInfo(ctx, commit.SKUID)
+			if errors.Is(err, sql.ErrNoRows) {
+				out.Unavailable = append(out.Unavailable, term.name)
+				continue
+			}
+			if err != nil {
+				return nil, CompareCommitmentsOutput{}, err
+			}
+			if cp.UsageUnit != p.UsageUnit {
+				return nil, CompareCommitmentsOutput{}, fmt.Errorf("commitment SKU %s is priced per %s, the on-demand SKU per %s", commit.SKUID, cp.UsageUnit, p.UsageUnit)
+			}
+			// A commitment is paid for every hour whatever the utilization.
+			cost, err := pricing.Calculate(cp, in.Quantity*hours)
+			if err != nil {
+				return nil, CompareCommitmentsOutput{}, err
+			}
+			t := CommitmentTerm{
+				Term:           term.name,
+				SKUID:          commit.SKUID,
+				Description:    commit.Description,
+				UnitPrice:      pricing.BaseUnitPrice(cp),
+				MonthlyCost:    cost.Total,
+				MonthlySavings: onDemandMonthly - cost.Total,
+			}
+			t.TotalSavings = t.MonthlySavings * float64(term.months)
+			if onDemandMonthly > 0 {
+				t.SavingsPercent = 100 * t.MonthlySavings / onDemandMonthly
+			}
+			if t.BreakevenUtilization, err = breakeven(onDemand, cost.Total); err != nil {
+				return nil, CompareCommitmentsOutput{}, err
+			}
+			if cost.Total < cheapest {
+				cheapest, out.Cheapest = cost.Total, term.name
+			}
+			out.Commitments = append(out.Commitments, t)
+		}
+		if len(out.Commitments) == 0 {
+			return nil, CompareCommitmentsOutput{}, fmt.Errorf("no commitment SKU covers %q in %s", sku.Description, region)
+		}
+		return nil, out, nil
+	}
+	return tool, handler
+}
+
+// skuPricing returns the SKU called skuID and its latest pricing.
+func skuPricing(ctx context.Context, skus SKUStore, skuID string) (database.SKU, database.PricingInfo, error) {
+	sku, err := skus.SKUByID(ctx, skuID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.SKU{}, database.PricingInfo{}, fmt.Errorf("SKU %q not found", skuID)
+	}
+	if err != nil {
+		return database.SKU{}, database.PricingInfo{}, err
+	}
+	p, err := skus.LatestPricingInfo(ctx, skuID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.SKU{}, database.PricingInfo{}, fmt.Errorf("SKU %q has no pricing", skuID)
+	}
+	return sku, p, err
+}
+
+// commitmentSKU finds the SKU of usageType covering the on-demand sku in
+// region: one of the same service and resource family, preferring the same
+// resource group, then a description naming the same machine family, such
+// as "N2" for "N2 Instance Core". It returns a zero SKU when none matches.
+func commitmentSKU(ctx context.Context, catalog CatalogStore, sku database.SKU, usageType, region string) (database.SKU, error) {
+	matches, err := catalog.SearchSKUs(ctx, database.SKUFilter{
+		ServiceID:      sku.ServiceID,
+		ResourceFamily: sku.Category.ResourceFamily,
+		UsageType:      usageType,
+		Region:         region,
+		Limit:          compareLimit,
+	})
+	if err != nil {
+		return database.SKU{}, err
+	}
+	narrow := func(keep func(database.SKU) bool) {
+		var kept []database.SKU
+		for _, m := range matches {
+			if keep(m) {
+				kept = append(kept, m)
+			}
+		}
+		if len(kept) > 0 {
+			matches = kept
+		}
+	}
+	narrow(func(m database.SKU) bool { return m.Category.ResourceGroup == sku.Category.ResourceGroup })
+	if family, _, ok := strings.Cut(sku.Description, " "); ok && len(matches) > 1 {
+		narrow(func(m database.SKU) bool { return slices.Contains(strings.Fields(m.Description), family) })
+	}
+	switch len(matches) {
+	case 0:
+		return database.SKU{}, nil
+	case 1:
+		return matches[0], nil
+	default:
+		return database.SKU{}, fmt.Errorf("several %s SKUs match %q in %s, such as %q and %q", usageType, sku.Description, region, matches[0].Description, matches[1].Description)
+	}
+}
+
+// breakeven returns the utilization at which onDemand costs as much as
+// commitment, or nil when on-demand stays cheaper at full utilization. The
+// on-demand cost grows with utilization, so it is found by bisection.
+func breakeven(onDemand func(float64) (float64, error), commitment float64) (*float64, error) {
+	full, err := onDemand(1)
+	if err != nil || full <= commitment {
+		return nil, err
+	}
+	lo, hi := 0.0, 1.0
+	for range 50 {
+		mid := (lo + hi) / 2
+		c, err := onDemand(mid)
+		if err != nil {
+			return nil, err
+		}
+		if c < commitment {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	u := hi
+	return &u, nil
+}
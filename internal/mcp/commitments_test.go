@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"mcp-server/internal/database"
+)
+
+// usageTypeCatalog returns the SKUs of the usage type searched for.
+type usageTypeCatalog struct {
+	*fakeCatalog
+}
+
+func (c usageTypeCatalog) SearchSKUs(ctx context.Context, f database.SKUFilter) ([]database.SKU, error) {
+	var out []database.SKU
+	for _, s := range c.skus {
+		if s.Category.UsageType == f.UsageType {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func TestCompareCommitmentsTool(t *testing.T) {
+	category := func(usageType string) database.Category {
+		return database.Category{ResourceFamily: "Compute", ResourceGroup: "CPU", UsageType: usageType}
+	}
+	us := []string{"us-central1"}
+	catalog := usageTypeCatalog{&fakeCatalog{regions: testRegions, skus: []database.SKU{
+		{SKUID: "c1", Description: "Commitment v1: E2 Cpu in Americas for 1 Year", Category: category("Commit1Yr"), ServiceRegions: us},
+		{SKUID: "c1-n2", Description: "Commitment v1: N2 Cpu in Americas for 1 Year", Category: category("Commit1Yr"), ServiceRegions: us},
+		{SKUID: "c3", Description: "Commitment v1: E2 Cpu in Americas for 3 Year", Category: category("Commit3Yr"), ServiceRegions: us},
+	}}}
+	skus := fakeSKUs{
+		skus: map[string]database.SKU{
+			"od": {SKUID: "od", Description: "E2 Instance Core running in Americas", Category: category("OnDemand"), ServiceRegions: us},
+		},
+		prices: map[string]database.PricingInfo{
+			"od": price("od", 20_000_000),
+			"c1": price("c1", 14_000_000),
+			"c3": price("c3", 10_000_000),
+		},
+	}
+	_, handler := compareCommitmentsTool(skus, catalog)
+	ctx := context.Background()
+
+	_, out, err := handler(ctx, nil, CompareCommitmentsInput{SKUID: "od", Quantity: 10})
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	}
+	// On-demand 10 vCPUs: 10*730*0.02 = 146 a month.
+	if out.Region != "us-central1" || math.Abs(out.OnDemandMonthly-146) > 1e-9 || len(out.Commitments) != 2 || out.Cheapest != "3-year" {
+		t.Fatalf("output = %+v", out)
+	}
+	one := out.Commitments[0]
+	if one.SKUID != "c1" || math.Abs(one.MonthlyCost-102.2) > 1e-9 || math.Abs(one.TotalSavings-12*43.8) > 1e-9 || math.Abs(one.SavingsPercent-30) > 1e-9 {
+		t.Fatalf("1-year term = %+v", one)
+	}
+	if one.BreakevenUtilization == nil || math.Abs(*one.BreakevenUtilization-0.7) > 1e-6 {
+		t.Fatalf("1-year breakeven = %v", one.BreakevenUtilization)
+	}
+
+	// At 40% utilization on-demand is cheaper than either commitment.
+	_, out, err = handler(ctx, nil, CompareCommitmentsInput{SKUID: "od", Quantity: 10, Utilization: 0.4})
+	if err != nil || out.Cheapest != "on-demand" || out.Commitments[1].MonthlySavings >= 0 {
+		t.Fatalf("low utilization output = %+v, %v", out, err)
+	}
+
+	for _, tt := range []struct {
+		in   CompareCommitmentsInput
+		want string
+	}{
+		{CompareCommitmentsInput{SKUID: "od"}, "quantity"},
+		{CompareCommitmentsInput{SKUID: "od", Quantity: 1, Utilization: 2}, "utilization"},
+		{CompareCommitmentsInput{SKUID: "od", Quantity: 1, Region: "europe-west1"}, "not offered in europe-west1"},
+		{CompareCommitmentsInput{SKUID: "missing", Quantity: 1}, "not found"},
+	} {
+		if _, _, err := handler(ctx, nil, tt.in); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("compare %+v: got %v, want error containing %q", tt.in, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/database"
+	"mcp-server/internal/pricing"
+)
+
+// compareLimit bounds the SKUs a comparison considers; the output reports
+// when more matched.
+const compareLimit = 1000
+
+// CompareRegionsInput selects a family of equivalent SKUs, one per region.
+type CompareRegionsInput struct {
+	Description    string `json:"description,omitempty" jsonschema:"SKU description pattern, * matches any text, such as N2 Instance Core running in *"`
+	ResourceFamily string `json:"resource_family,omitempty" jsonschema:"resource family such as Compute"`
+	ResourceGroup  string `json:"resource_group,omitempty" jsonschema:"resource group such as CPU or RAM"`
+	UsageType      string `json:"usage_type,omitempty" jsonschema:"usage type such as OnDemand or Preemptible"`
+	ServiceID      string `json:"service_id,omitempty" jsonschema:"service ID to restrict the comparison to"`
+	BaselineRegion string `json:"baseline_region,omitempty" jsonschema:"region deltas are computed against, default us-central1"`
+}
+
+// RegionPrice is the price of the equivalent SKU in one region.
+type RegionPrice struct {
+	Rank         int     `json:"rank"`
+	Region       string  `json:"region"`
+	RegionName   string  `json:"region_name,omitempty"`
+	SKUID        string  `json:"sku_id"`
+	Description  string  `json:"description"`
+	UnitPrice    float64 `json:"unit_price"`
+	Delta        float64 `json:"delta"`
+	DeltaPercent float64 `json:"delta_percent"`
+}
+
+// RegionComparison ranks the regions of one group of equivalent SKUs from
+// cheapest to most expensive.
+type RegionComparison struct {
+	// Description is a pattern matching the SKUs of the group, such as
+	// "N2 Instance Core running in *".
+	Description   string        `json:"description"`
+	Currency      string        `json:"currency"`
+	UsageUnit     string        `json:"usage_unit"`
+	BaselinePrice float64       `json:"baseline_price"`
+	Regions       []RegionPrice `json:"regions"`
+}
+
+// CompareRegionsOutput has one comparison per group of equivalent SKUs
+// offered in the baseline region, ordered by description.
+type CompareRegionsOutput struct {
+	BaselineRegion string             `json:"baseline_region"`
+	Comparisons    []RegionComparison `json:"comparisons"`
+	// Truncated reports that more SKUs matched than were compared, so some
+	// regions or groups may be missing.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+func compareRegionsTool(skus SKUStore, catalog CatalogStore) (*sdk.Tool, sdk.ToolHandlerFor[CompareRegionsInput, CompareRegionsOutput]) {
+	tool := &sdk.Tool{
+		Name: "compare_regions",
+		Description: "Finds the equivalent SKU in every region, by description pattern or resource family, group and usage type, " +
+			"and ranks regions by unit price with the difference to a baseline region. " +
+			"Matching SKUs are grouped by description up to the region, such as N2 Instance Core running in *, with one ranking per group.",
+	}
+	handler := func(ctx context.Context, req *sdk.CallToolRequest, in CompareRegionsInput) (*sdk.CallToolResult, CompareRegionsOutput, error) {
+		if in.Description == "" && (in.ResourceFamily == "" || in.ResourceGroup == "" || in.UsageType == "") {
+			return nil, CompareRegionsOutput{}, errors.New("set description or all of resource_family, resource_group and usage_type")
+		}
+		baseline := in.BaselineRegion
+		if baseline == "" {
+			baseline = "us-central1"
+		}
+		if err := validateRegion(ctx, catalog, baseline); err != nil {
+			return nil, CompareRegionsOutput{}, err
+		}
+		matches, err := catalog.SearchSKUs(ctx, database.SKUFilter{
+			DescriptionPattern: in.Description,
+			ServiceID:          in.ServiceID,
+			ResourceFamily:     in.ResourceFamily,
+			ResourceGroup:      in.ResourceGroup,
+			UsageType:          in.UsageType,
+			Limit:              compareLimit + 1,
+		})
+		if err != nil {
+			return nil, CompareRegionsOutput{}, err
+		}
+		if len(matches) == 0 {
+			return nil, CompareRegionsOutput{}, errors.New("no SKU matches; use search to find the description of the SKU in one region")
+		}
+		out := CompareRegionsOutput{BaselineRegion: baseline, Comparisons: []RegionComparison{}}
+		if len(matches) > compareLimit {
+			matches, out.Truncated = matches[:compareLimit], true
+		}
+		names, err := regionNames(ctx, catalog)
+		if err != nil {
+			return nil, CompareRegionsOutput{}, err
+		}
+
+		groups := map[string]*RegionComparison{}
+		byRegion := map[string]map[string]RegionPrice{}
+		for _, sku := range matches {
+			p, err := skus.LatestPricingInfo(ctx, sku.SKUID)
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			if err != nil {
+				return nil, CompareRegionsOutput{}, err
+			}
+			key := equivalenceKey(sku.Description)
+			g, ok := groups[key]
+			if !ok {
+				g = &RegionComparison{Description: key, Currency: p.CurrencyCode, UsageUnit: p.UsageUnit}
+				groups[key], byRegion[key] = g, map[string]RegionPrice{}
+			} else if p.UsageUnit != g.UsageUnit {
+				return nil, CompareRegionsOutput{}, fmt.Errorf("SKUs matching %q are priced in different units (%s and %s); narrow the criteria", key, g.UsageUnit, p.UsageUnit)
+			}
+			for _, region := range sku.ServiceRegions {
+				if other, ok := byRegion[key][region]; ok {
+					return nil, CompareRegionsOutput{}, fmt.Errorf("several SKUs match %q in %s (%s and %s); narrow the criteria, for example with service_id", key, region, other.SKUID, sku.SKUID)
+				}
+				byRegion[key][region] = RegionPrice{
+					Region:      region,
+					RegionName:  names[region],
+					SKUID:       sku.SKUID,
+					Description: sku.Description,
+					UnitPrice:   pricing.BaseUnitPrice(p),
+				}
+			}
+		}
+		for key, g := range groups {
+			base, ok := byRegion[key][baseline]
+			if !ok {
+				continue
+			}
+			g.BaselinePrice = base.UnitPrice
+			for _, r := range byRegion[key] {
+				r.Delta = r.UnitPrice - base.UnitPrice
+				if base.UnitPrice != 0 {
+					r.DeltaPercent = 100 * r.Delta / base.UnitPrice
+				}
+				g.Regions = append(g.Regions, r)
+			}
+			sort.Slice(g.Regions, func(i, j int) bool {
+				a, b := g.Regions[i], g.Regions[j]
+				if a.UnitPrice != b.UnitPrice {
+					return a.UnitPrice < b.UnitPrice
+				}
+				return a.Region < b.Region
+			})
+			for i := range g.Regions {
+				g.Regions[i].Rank = i + 1
+			}
+			out.Comparisons = append(out.Comparisons, *g)
+		}
+		if len(out.Comparisons) == 0 {
+			return nil, CompareRegionsOutput{}, fmt.Errorf("no matching SKU is offered in the baseline region %s", baseline)
+		}
+		sort.Slice(out.Comparisons, func(i, j int) bool {
+			return out.Comparisons[i].Description < out.Comparisons[j].Description
+		})
+		return nil, out, nil
+	}
+	return tool, handler
+}
+
+// equivalenceKey returns the description pattern shared by the regional
+// variants of a SKU: its description up to the last " in ", which names the
+// region or continent, followed by "*". Descriptions without " in " are their
+// own key.
+func equivalenceKey(description string) string {
+	i := strings.LastIndex(description, " in ")
+	if i < 0 {
+		return description
+	}
+	return description[:i+len(" in ")] + "*"
+}
+
+// regionNames maps region IDs to display names.
+func regionNames(ctx context.Context, catalog CatalogStore) (map[string]string, error) {
+	regions, err := catalog.ListRegions(ctx, database.RegionFilter{})
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(regions))
+	for _, g := range regions {
+		names[g.RegionID] = g.DisplayName
+	}
+	return names, nil
+}
@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"mcp-server/internal/database"
+)
+
+func price(sku string, nanos int32) database.PricingInfo {
+	return database.PricingInfo{SKUID: sku, CurrencyCode: "USD", UsageUnit: "h",
+		TieredRates: []database.TieredRate{{UnitPrice: database.Money{Nanos: nanos}}}}
+}
+
+func TestCompareRegionsTool(t *testing.T) {
+	regional := []database.SKU{
+		{SKUID: "us", Description: "N2 Instance Core running in Americas", ServiceRegions: []string{"us-central1", "us-east1"}},
+		{SKUID: "eu", Description: "N2 Instance Core running in EMEA", ServiceRegions: []string{"europe-west1"}},
+	}
+	skus := fakeSKUs{prices: map[string]database.PricingInfo{
+		"us": price("us", 30_000_000),
+		"eu": price("eu", 36_000_000),
+	}}
+	catalog := &fakeCatalog{regions: testRegions, skus: regional}
+	_, handler := compareRegionsTool(skus, catalog)
+	ctx := context.Background()
+
+	_, out, err := handler(ctx, nil, CompareRegionsInput{Description: "N2 Instance Core running in *"})
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	}
+	if catalog.filter.DescriptionPattern != "N2 Instance Core running in *" {
+		t.Fatalf("filter = %+v", catalog.filter)
+	}
+	if out.BaselineRegion != "us-central1" || out.Truncated || len(out.Comparisons) != 1 {
+		t.Fatalf("output = %+v", out)
+	}
+	n2 := out.Comparisons[0]
+	if n2.Description != "N2 Instance Core running in *" || n2.BaselinePrice != 0.03 || len(n2.Regions) != 3 {
+		t.Fatalf("comparison = %+v", n2)
+	}
+	last := n2.Regions[2]
+	if last.Rank != 3 || last.Region != "europe-west1" || last.RegionName != "St. Ghislain" || last.DeltaPercent < 19.99 || last.DeltaPercent > 20.01 {
+		t.Fatalf("most expensive region = %+v", last)
+	}
+	if n2.Regions[0].Region != "us-central1" || n2.Regions[0].Delta != 0 {
+		t.Fatalf("cheapest region = %+v", n2.Regions[0])
+	}
+
+	if _, out, err := handler(ctx, nil, CompareRegionsInput{Description: "N2*", BaselineRegion: "europe-west1"}); err != nil || out.Comparisons[0].Regions[0].DeltaPercent > -16 {
+		t.Fatalf("European baseline = %+v, %v", out, err)
+	}
+
+	// A resource family, group and usage type match every machine family;
+	// each gets its own ranking.
+	catalog.skus = append(regional, database.SKU{SKUID: "n2d", Description: "N2D AMD Instance Core running in Americas", ServiceRegions: []string{"us-central1"}})
+	skus.prices["n2d"] = price("n2d", 27_000_000)
+	_, out, err = handler(ctx, nil, CompareRegionsInput{ResourceFamily: "Compute", ResourceGroup: "CPU", UsageType: "OnDemand"})
+	if err != nil {
+		t.Fatalf("compare by category: %v", err)
+	}
+	if len(out.Comparisons) != 2 || out.Comparisons[0].Description != "N2 Instance Core running in *" || out.Comparisons[1].Regions[0].SKUID != "n2d" {
+		t.Fatalf("comparisons by category = %+v", out.Comparisons)
+	}
+
+	many := make([]database.SKU, compareLimit+1)
+	for i := range many {
+		id := fmt.Sprintf("sku-%d", i)
+		many[i] = database.SKU{SKUID: id, Description: id + " in Americas", ServiceRegions: []string{"us-central1"}}
+		skus.prices[id] = price(id, 1_000_000)
+	}
+	catalog.skus = many
+	if _, out, err := handler(ctx, nil, CompareRegionsInput{Description: "sku-*"}); err != nil || !out.Truncated || len(out.Comparisons) != compareLimit {
+		t.Fatalf("compare past the limit: truncated %v, %d comparisons, %v", out.Truncated, len(out.Comparisons), err)
+	}
+
+	catalog.skus = append(regional, database.SKU{SKUID: "us-2", Description: "N2 Instance Core running in Americas", ServiceRegions: []string{"us-central1"}})
+	skus.prices["us-2"] = price("us-2", 31_000_000)
+	for _, tt := range []struct {
+		in   CompareRegionsInput
+		want string
+	}{
+		{CompareRegionsInput{Description: "N2*"}, `several SKUs match "N2 Instance Core running in *" in us-central1`},
+		{CompareRegionsInput{ResourceFamily: "Compute"}, "set description"},
+		{CompareRegionsInput{Description: "N2*", BaselineRegion: "moon-1"}, "unknown region"},
+	} {
+		if _, _, err := handler(ctx, nil, tt.in); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("compare %+v: got %v, want error containing %q", tt.in, err, tt.want)
+		}
+	}
+}
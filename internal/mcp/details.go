@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/cache"
+	"mcp-server/internal/database"
+	"mcp-server/internal/pricing"
+)
+
+// SKUStore looks up SKUs and their current pricing.
+type SKUStore interface {
+	SKUByID(ctx context.Context, skuID string) (database.SKU, error)
+	LatestPricingInfo(ctx context.Context, skuID string) (database.PricingInfo, error)
+}
+
+var (
+	_ SKUStore = (*database.SQLRepository)(nil)
+	_ SKUStore = (*cache.Cache)(nil)
+)
+
+// DetailsInput selects a SKU.
+type DetailsInput struct {
+	SKUID string `json:"sku_id" jsonschema:"SKU ID such as 6F81-5844-456A"`
+}
+
+// PriceTier is the unit price from a usage amount onwards.
+type PriceTier struct {
+	StartUsageAmount float64 `json:"start_usage_amount"`
+	UnitPrice        float64 `json:"unit_price"`
+}
+
+// Pricing is the current price of a SKU.
+type Pricing struct {
+	EffectiveTime        string      `json:"effective_time"`
+	Summary              string      `json:"summary,omitempty"`
+	Currency             string      `json:"currency"`
+	UsageUnit            string      `json:"usage_unit"`
+	UsageUnitDescription string      `json:"usage_unit_description,omitempty"`
+	Tiers                []PriceTier `json:"tiers"`
+}
+
+// DetailsOutput describes a SKU and its current pricing.
+type DetailsOutput struct {
+	SKUID          string   `json:"sku_id"`
+	ServiceID      string   `json:"service_id"`
+	Name           string   `json:"name"`
+	Description    string   `json:"description"`
+	ResourceFamily string   `json:"resource_family,omitempty"`
+	ResourceGroup  string   `json:"resource_group,omitempty"`
+	UsageType      string   `json:"usage_type,omitempty"`
+	Regions        []string `json:"regions,omitempty"`
+	Pricing        *Pricing `json:"pricing,omitempty"`
+}
+
+func detailsTool(store SKUStore) (*sdk.Tool, sdk.ToolHandlerFor[DetailsInput, DetailsOutput]) {
+	tool := &sdk.Tool{
+		Name:        "details",
+		Description: "Returns the metadata of a Google Cloud SKU and its current pricing tiers.",
+	}
+	handler := func(ctx context.Context, req *sdk.CallToolRequest, in DetailsInput) (*sdk.CallToolResult, DetailsOutput, error) {
+		if in.SKUID == "" {
+			return nil, DetailsOutput{}, errors.New("sku_id is required")
+		}
+		sku, err := store.SKUByID(ctx, in.SKUID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, DetailsOutput{}, fmt.Errorf("SKU %q not found", in.SKUID)
+		}
+		if err != nil {
+			return nil, DetailsOutput{}, err
+		}
+		out := DetailsOutput{
+			SKUID:          sku.SKUID,
+			ServiceID:      sku.ServiceID,
+			Name:           sku.SkuName,
+			Description:    sku.Description,
+			ResourceFamily: sku.Category.ResourceFamily,
+			ResourceGroup:  sku.Category.ResourceGroup,
+			UsageType:      sku.Category.UsageType,
+			Regions:        sku.ServiceRegions,
+		}
+		p, err := store.LatestPricingInfo(ctx, in.SKUID)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+		case err != nil:
+			return nil, DetailsOutput{}, err
+		default:
+			out.Pricing = pricingOutput(p)
+		}
+		return nil, out, nil
+	}
+	return tool, handler
+}
+
+func pricingOutput(p database.PricingInfo) *Pricing {
+	out := &Pricing{
+		EffectiveTime:        p.EffectiveTime.UTC().Format(time.RFC3339),
+		Summary:              p.Summary,
+		Currency:             p.CurrencyCode,
+		UsageUnit:            p.UsageUnit,
+		UsageUnitDescription: p.UsageUnitDescription,
+		Tiers:                make([]PriceTier, 0, len(p.TieredRates)),
+	}
+	for _, r := range p.TieredRates {
+		out.Tiers = append(out.Tiers, PriceTier{
+			StartUsageAmount: r.StartUsageAmount,
+			UnitPrice:        pricing.UnitPrice(r.UnitPrice),
+		})
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"mcp-server/internal/database"
+)
+
+type fakeSKUs struct {
+	skus   map[string]database.SKU
+	prices map[string]database.PricingInfo
+}
+
+func (f fakeSKUs) SKUByID(ctx context.Context, skuID string) (database.SKU, error) {
+	s, ok := f.skus[skuID]
+	if !ok {
+		return database.SKU{}, sql.ErrNoRows
+	}
+	return s, nil
+}
+
+func (f fakeSKUs) LatestPricingInfo(ctx context.Context, skuID string) (database.PricingInfo, error) {
+	p, ok := f.prices[skuID]
+	if !ok {
+		return database.PricingInfo{}, sql.ErrNoRows
+	}
+	return p, nil
+}
+
+func TestDetailsTool(t *testing.T) {
+	store := fakeSKUs{
+		skus: map[string]database.SKU{
+			"cpu": {SKUID: "cpu", ServiceID: "compute", SkuName: "N1 core", Category: database.Category{ResourceFamily: "Compute"}},
+			"new": {SKUID: "new", ServiceID: "compute", SkuName: "Unpriced"},
+		},
+		prices: map[string]database.PricingInfo{
+			"cpu": {SKUID: "cpu", EffectiveTime: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), CurrencyCode: "USD", UsageUnit: "h",
+				TieredRates: []database.TieredRate{{UnitPrice: database.Money{Units: 1, Nanos: 500_000_000}}}},
+		},
+	}
+	_, handler := detailsTool(store)
+	ctx := context.Background()
+
+	_, out, err := handler(ctx, nil, DetailsInput{SKUID: "cpu"})
+	if err != nil {
+		t.Fatalf("details: %v", err)
+	}
+	if out.Name != "N1 core" || out.ResourceFamily != "Compute" || out.Pricing == nil {
+		t.Fatalf("output = %+v", out)
+	}
+	if len(out.Pricing.Tiers) != 1 || out.Pricing.Tiers[0].UnitPrice != 1.5 || out.Pricing.EffectiveTime != "2025-01-01T00:00:00Z" {
+		t.Fatalf("pricing = %+v", out.Pricing)
+	}
+
+	if _, out, err := handler(ctx, nil, DetailsInput{SKUID: "new"}); err != nil || out.Pricing != nil {
+		t.Fatalf("unpriced SKU = %+v, %v; want no pricing", out, err)
+	}
+	if _, _, err := handler(ctx, nil, DetailsInput{SKUID: "gone"}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("missing SKU: got %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/database"
+)
+
+// savePricing stores the total e was priced at at and, when it differs from
+// the total saved before, records the drift. It reports whether the total
+// changed.
+func savePricing(ctx context.Context, store EstimateStore, e database.Estimate, priced EstimateOutput, at time.Time) (bool, error) {
+	changed := priced.Currency != e.Currency || math.Abs(priced.Total-e.Total) > 1e-9
+	if changed {
+		err := store.InsertEstimateDrift(ctx, database.EstimateDrift{
+			EstimateID:       e.EstimateID,
+			Currency:         priced.Currency,
+			PreviousTotal:    e.Total,
+			Total:            priced.Total,
+			PreviousPricedAt: e.PricedAt,
+			PricedAt:         at,
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+	return changed, store.UpdateEstimatePricing(ctx, e.EstimateID, priced.Currency, priced.Total, at)
+}
+
+// unresolvedError describes the lines of priced that could not be priced, or
+// returns nil when all were. The total of such an estimate leaves them out, so
+// saving it would record a drop in price that did not happen.
+func unresolvedError(priced EstimateOutput) error {
+	if len(priced.Unresolved) == 0 {
+		return nil
+	}
+	lines := make([]string, len(priced.Unresolved))
+	for i, u := range priced.Unresolved {
+		lines[i] = fmt.Sprintf("line %d: %s", u.Index, u.Error)
+	}
+	return fmt.Errorf("%d of %d lines do not resolve: %s", len(priced.Unresolved), len(priced.Unresolved)+len(priced.Lines), strings.Join(lines, "; "))
+}
+
+// EstimateRepricer re-prices every saved estimate against current pricing,
+// for the sync job to run once new pricing is stored.
+type EstimateRepricer struct {
+	skus      SKUStore
+	catalog   CatalogStore
+	estimates EstimateStore
+}
+
+// NewEstimateRepricer returns an EstimateRepricer pricing estimates from skus
+// and catalog.
+func NewEstimateRepricer(skus SKUStore, catalog CatalogStore, estimates EstimateStore) *EstimateRepricer {
+	return &EstimateRepricer{skus: skus, catalog: catalog, estimates: estimates}
+}
+
+// RepriceEstimates stores the current total of every saved estimate, records
+// the drift of those that changed and returns their number. An estimate that
+// cannot be priced, including one with lines that no longer resolve, is logged
+// and skipped, and its error returned once the others are done.
+func (r *EstimateRepricer) RepriceEstimates(ctx context.Context) (int, error) {
+	estimates, err := r.estimates.ListEstimates(ctx, "")
+	if err != nil {
+		return 0, err
+	}
+	at := time.Now().UTC()
+	changed := 0
+	var errs []error
+	for _, e := range estimates {
+		ok, err := r.reprice(ctx, e, at)
+		if err != nil {
+			slog.WarnContext(ctx, "reprice estimate", "estimate_id", e.EstimateID, "error", err)
+			errs = append(errs, fmt.Errorf("estimate %s: %w", e.EstimateID, err))
+			continue
+		}
+		if ok {
+			changed++
+		}
+	}
+	return changed, errors.Join(errs...)
+}
+
+func (r *EstimateRepricer) reprice(ctx context.Context, e database.Estimate, at time.Time) (bool, error) {
+	var input EstimateInput
+	if err := json.Unmarshal(e.Input, &input); err != nil {
+		return false, fmt.Errorf("decode: %w", err)
+	}
+	priced, err := estimate(ctx, r.skus, r.catalog, input)
+	if err != nil {
+		return false, err
+	}
+	if err := unresolvedError(priced); err != nil {
+		return false, err
+	}
+	return savePricing(ctx, r.estimates, e, priced, at)
+}
+
+// EstimateDriftInput selects the drift of your saved estimates.
+type EstimateDriftInput struct {
+	EstimateID string `json:"estimate_id,omitempty" jsonschema:"only the drift of this saved estimate"`
+	Days       int    `json:"days,omitempty" jsonschema:"only drift from the last days; default all"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"maximum number of changes to return; default 100"`
+}
+
+// DriftChange is one change of an estimate's total between two pricings.
+type DriftChange struct {
+	EstimateID       string    `json:"estimate_id"`
+	Name             string    `json:"name"`
+	Currency         string    `json:"currency"`
+	PreviousTotal    float64   `json:"previous_total"`
+	Total            float64   `json:"total"`
+	Delta            float64   `json:"delta"`
+	DeltaPercent     float64   `json:"delta_percent"`
+	PreviousPricedAt time.Time `json:"previously_priced_at"`
+	PricedAt         time.Time `json:"priced_at"`
+}
+
+// EstimateMovement is the net change of one estimate over the selected
+// changes.
+type EstimateMovement struct {
+	EstimateID   string  `json:"estimate_id"`
+	Name         string  `json:"name"`
+	Currency     string  `json:"currency"`
+	From         float64 `json:"from"`
+	To           float64 `json:"to"`
+	Delta        float64 `json:"delta"`
+	DeltaPercent float64 `json:"delta_percent"`
+	Changes      int     `json:"changes"`
+}
+
+// EstimateDriftOutput lists the estimates that moved, largest net change
+// first, and the individual changes, newest first.
+type EstimateDriftOutput struct {
+	Estimates []EstimateMovement `json:"estimates"`
+	Changes   []DriftChange      `json:"changes"`
+}
+
+func estimateDriftTool(store EstimateStore) (*sdk.Tool, sdk.ToolHandlerFor[EstimateDriftInput, EstimateDriftOutput]) {
+	tool := &sdk.Tool{
+		Name: "estimate_drift",
+		Description: "Lists which of your saved estimates changed in total when re-priced after catalog syncs or with reprice_estimate, " +
+			"and by how much, with each change and the net change per estimate.",
+	}
+	handler := func(ctx context.Context, req *sdk.CallToolRequest, in EstimateDriftInput) (*sdk.CallToolResult, EstimateDriftOutput, error) {
+		me, err := owner(req)
+		if err != nil {
+			return nil, EstimateDriftOutput{}, err
+		}
+		if in.Days < 0 || in.Limit < 0 {
+			return nil, EstimateDriftOutput{}, errors.New("days and limit must not be negative")
+		}
+		f := database.EstimateDriftFilter{Owner: me, EstimateID: in.EstimateID, Limit: in.Limit}
+		if in.Days > 0 {
+			f.Since = time.Now().UTC().AddDate(0, 0, -in.Days)
+		}
+		drift, err := store.ListEstimateDrift(ctx, f)
+		if err != nil {
+			return nil, EstimateDriftOutput{}, err
+		}
+		return nil, driftOutput(drift), nil
+	}
+	return tool, handler
+}
+
+// driftOutput summarizes drift, which is ordered newest first.
+func driftOutput(drift []database.EstimateDrift) EstimateDriftOutput {
+	out := EstimateDriftOutput{Estimates: []EstimateMovement{}, Changes: make([]DriftChange, 0, len(drift))}
+	byID := map[string]*EstimateMovement{}
+	var order []string
+	for _, d := range drift {
+		out.Changes = append(out.Changes, DriftChange{
+			EstimateID:       d.EstimateID,
+			Name:             d.Name,
+			Currency:         d.Currency,
+			PreviousTotal:    d.PreviousTotal,
+			Total:            d.Total,
+			Delta:            d.Total - d.PreviousTotal,
+			DeltaPercent:     percentChange(d.PreviousTotal, d.Total),
+			PreviousPricedAt: d.PreviousPricedAt,
+			PricedAt:         d.PricedAt,
+		})
+		m, ok := byID[d.EstimateID]
+		if !ok {
+			m = &EstimateMovement{EstimateID: d.EstimateID, Name: d.Name, Currency: d.Currency, To: d.Total}
+			byID[d.EstimateID] = m
+			order = append(order, d.EstimateID)
+		}
+		// Older changes come later, so the last one seen holds the total
+		// the estimate started from.
+		m.From = d.PreviousTotal
+		m.Changes++
+	}
+	for _, id := range order {
+		m := byID[id]
+		m.Delta = m.To - m.From
+		m.DeltaPercent = percentChange(m.From, m.To)
+		out.Estimates = append(out.Estimates, *m)
+	}
+	sort.SliceStable(out.Estimates, func(i, j int) bool {
+		return math.Abs(out.Estimates[i].Delta) > math.Abs(out.Estimates[j].Delta)
+	})
+	return out
+}
+
+// percentChange returns the change from before to after in percent, or 0
+// when before is 0.
+func percentChange(before, after float64) float64 {
+	if before == 0 {
+		return 0
+	}
+	return 100 * (after - before) / before
+}
@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"mcp-server/internal/database"
+)
+
+func TestEstimateRepricer(t *testing.T) {
+	skus := fakeSKUs{
+		skus:   map[string]database.SKU{"cpu": {SKUID: "cpu", Description: "E2 Instance Core running in Americas"}},
+		prices: map[string]database.PricingInfo{"cpu": price("cpu", 22_000_000)},
+	}
+	store := newFakeEstimates()
+	priced := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	for _, e := range []database.Estimate{
+		{EstimateID: "moved", Owner: "github:alice", Input: []byte(`{"lines":[{"sku_id":"cpu","quantity":730}]}`), Currency: "USD", Total: 14.6, PricedAt: priced},
+		{EstimateID: "steady", Owner: "github:bob", Input: []byte(`{"lines":[{"sku_id":"cpu","quantity":100}]}`), Currency: "USD", Total: 2.2, PricedAt: priced},
+		{EstimateID: "broken", Owner: "github:bob", Input: []byte(`not json`), Currency: "USD", Total: 1, PricedAt: priced},
+		{EstimateID: "dropped", Owner: "github:bob", Input: []byte(`{"lines":[{"sku_id":"cpu","quantity":730},{"sku_id":"gone","quantity":10}]}`), Currency: "USD", Total: 20, PricedAt: priced},
+	} {
+		store.byID[e.EstimateID] = e
+	}
+
+	changed, err := NewEstimateRepricer(skus, &fakeCatalog{}, store).RepriceEstimates(context.Background())
+	if changed != 1 || err == nil || !strings.Contains(err.Error(), "estimate broken") || !strings.Contains(err.Error(), "estimate dropped: 1 of 2 lines do not resolve") {
+		t.Fatalf("RepriceEstimates = %d, %v; want 1 and errors for the broken and dropped estimates", changed, err)
+	}
+	if len(store.drift) != 1 {
+		t.Fatalf("drift = %+v, want one record", store.drift)
+	}
+	d := store.drift[0]
+	if d.EstimateID != "moved" || d.PreviousTotal != 14.6 || math.Abs(d.Total-16.06) > 1e-9 || !d.PreviousPricedAt.Equal(priced) {
+		t.Fatalf("drift = %+v", d)
+	}
+	if e := store.byID["moved"]; math.Abs(e.Total-16.06) > 1e-9 || !e.PricedAt.Equal(d.PricedAt) {
+		t.Fatalf("moved estimate = %+v", e)
+	}
+	if e := store.byID["steady"]; !e.PricedAt.After(priced) {
+		t.Fatalf("unchanged estimate was not marked repriced: %+v", e)
+	}
+	// The SKU of a line is gone: its total without that line is not a drop.
+	if e := store.byID["dropped"]; e.Total != 20 || !e.PricedAt.Equal(priced) {
+		t.Fatalf("estimate with an unresolved line was repriced: %+v", e)
+	}
+}
+
+func TestEstimateDriftTool(t *testing.T) {
+	store := newFakeEstimates()
+	store.byID["a"] = database.Estimate{EstimateID: "a", Owner: "github:alice", Name: "web"}
+	store.byID["b"] = database.Estimate{EstimateID: "b", Owner: "github:alice", Name: "batch"}
+	store.byID["c"] = database.Estimate{EstimateID: "c", Owner: "github:bob", Name: "other"}
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	store.drift = []database.EstimateDrift{
+		{EstimateID: "a", Currency: "USD", PreviousTotal: 100, Total: 110, PricedAt: day},
+		{EstimateID: "b", Currency: "USD", PreviousTotal: 50, Total: 20, PricedAt: day},
+		{EstimateID: "a", Currency: "USD", PreviousTotal: 110, Total: 120, PricedAt: day.AddDate(0, 0, 1)},
+		{EstimateID: "c", Currency: "USD", PreviousTotal: 1, Total: 2, PricedAt: day},
+	}
+	_, handler := estimateDriftTool(store)
+
+	_, out, err := handler(context.Background(), callAs("alice"), EstimateDriftInput{})
+	if err != nil {
+		t.Fatalf("estimate_drift: %v", err)
+	}
+	if len(out.Changes) != 3 || out.Changes[0].Total != 120 || out.Changes[0].DeltaPercent <= 9 {
+		t.Fatalf("changes = %+v", out.Changes)
+	}
+	want := []EstimateMovement{
+		{EstimateID: "b", Name: "batch", Currency: "USD", From: 50, To: 20, Delta: -30, DeltaPercent: -60, Changes: 1},
+		{EstimateID: "a", Name: "web", Currency: "USD", From: 100, To: 120, Delta: 20, DeltaPercent: 20, Changes: 2},
+	}
+	if len(out.Estimates) != len(want) {
+		t.Fatalf("estimates = %+v, want %+v", out.Estimates, want)
+	}
+	for i, m := range out.Estimates {
+		if m != want[i] {
+			t.Errorf("estimates[%d] = %+v, want %+v", i, m, want[i])
+		}
+	}
+
+	if _, out, _ := handler(context.Background(), callAs("alice"), EstimateDriftInput{EstimateID: "c"}); len(out.Changes) != 0 {
+		t.Fatalf("alice sees bob's drift: %+v", out.Changes)
+	}
+	if _, _, err := handler(context.Background(), callAs("alice"), EstimateDriftInput{Days: -1}); err == nil {
+		t.Fatal("negative days accepted")
+	}
+}
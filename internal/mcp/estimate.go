@@ -0,0 +1,243 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/database"
+)
+
+// maxEstimateLines bounds the line items of one estimate.
+const maxEstimateLines = 200
+
+// EstimateLineInput is one line item of a bill of materials: a SKU, given by
+// ID or found by description, and its monthly usage.
+type EstimateLineInput struct {
+	Name          string  `json:"name,omitempty" jsonschema:"label for the line, such as web frontend vCPUs"`
+	SKUID         string  `json:"sku_id,omitempty" jsonschema:"SKU ID; alternatively set description"`
+	Description   string  `json:"description,omitempty" jsonschema:"SKU description pattern, * matches any text; must match one SKU"`
+	ServiceID     string  `json:"service_id,omitempty" jsonschema:"service ID narrowing the description lookup"`
+	Region        string  `json:"region,omitempty" jsonschema:"region the usage runs in"`
+	Quantity      float64 `json:"quantity" jsonschema:"monthly usage in unit"`
+	Unit          string  `json:"unit,omitempty" jsonschema:"unit of quantity, such as h, GB or TiB.mo, converted to the SKU's usage unit; default the usage unit"`
+	Months        float64 `json:"months,omitempty" jsonschema:"months the usage lasts, default 1"`
+	HoursPerMonth float64 `json:"hours_per_month,omitempty" jsonschema:"hours per month each resource runs, for sustained use discounts, such as 730 for a full month; no discount when unset"`
+}
+
+// EstimateInput is a bill of materials.
+type EstimateInput struct {
+	Lines          []EstimateLineInput `json:"lines" jsonschema:"line items to price"`
+	IgnoreFreeTier bool                `json:"ignore_free_tier,omitempty" jsonschema:"do not deduct monthly free allowances"`
+	MonthHours     float64             `json:"month_hours,omitempty" jsonschema:"hours in a month for every line, for converting units such as h and mo and for sustained use discounts; default 730"`
+}
+
+// EstimateLine is the cost of one resolved line item.
+type EstimateLine struct {
+	Index       int        `json:"index"`
+	Name        string     `json:"name,omitempty"`
+	SKUID       string     `json:"sku_id"`
+	Description string     `json:"description"`
+	Service     string     `json:"service"`
+	Region      string     `json:"region"`
+	Quantity    float64    `json:"quantity"`
+	UsageUnit   string     `json:"usage_unit"`
+	Months      float64    `json:"months"`
+	MonthlyCost float64    `json:"monthly_cost"`
+	Discounts   []Discount `json:"discounts,omitempty"`
+	Cost        float64    `json:"cost"`
+}
+
+// UnresolvedLine is a line item that could not be priced.
+type UnresolvedLine struct {
+	Index int    `json:"index"`
+	Name  string `json:"name,omitempty"`
+	Error string `json:"error"`
+}
+
+// Subtotal is the cost of the lines sharing a service or region.
+type Subtotal struct {
+	Key  string  `json:"key"`
+	Cost float64 `json:"cost"`
+}
+
+// EstimateOutput prices a bill of materials line by line, with subtotals
+// ordered by decreasing cost.
+type EstimateOutput struct {
+	Currency   string           `json:"currency"`
+	Lines      []EstimateLine   `json:"lines"`
+	Unresolved []UnresolvedLine `json:"unresolved,omitempty"`
+	ByService  []Subtotal       `json:"by_service"`
+	ByRegion   []Subtotal       `json:"by_region"`
+	Total      float64          `json:"total"`
+}
+
+func estimateTool(skus SKUStore, catalog CatalogStore) (*sdk.Tool, sdk.ToolHandlerFor[EstimateInput, EstimateOutput]) {
+	tool := &sdk.Tool{
+		Name: "estimate",
+		Description: "Prices a bill of materials of many SKUs in one call, each given by SKU ID or description with its monthly usage, " +
+			"returning per-line costs, subtotals by service and region, a grand total and the lines that could not be resolved.",
+	}
+	handler := func(ctx context.Context, req *sdk.CallToolRequest, in EstimateInput) (*sdk.CallToolResult, EstimateOutput, error) {
+		out, err := estimate(ctx, skus, catalog, in)
+		if err != nil {
+			return nil, EstimateOutput{}, err
+		}
+		return nil, out, nil
+	}
+	return tool, handler
+}
+
+// estimate prices every line of in with the single-SKU calculator. Lines that
+// fail are reported as unresolved rather than failing the estimate. A free
+// allowance is granted once per billing account, so lines using the same one
+// share it in order until it is used up.
+func estimate(ctx context.Context, skus SKUStore, catalog CatalogStore, in EstimateInput) (EstimateOutput, error) {
+	if len(in.Lines) == 0 {
+		return EstimateOutput{}, errors.New("lines is required")
+	}
+	if len(in.Lines) > maxEstimateLines {
+		return EstimateOutput{}, fmt.Errorf("%d lines exceed the limit of %d", len(in.Lines), maxEstimateLines)
+	}
+	out := EstimateOutput{Lines: []EstimateLine{}}
+	byService, byRegion := map[string]float64{}, map[string]float64{}
+	usedAllowances := map[string]float64{}
+	for i, l := range in.Lines {
+		line, err := estimateLine(ctx, skus, catalog, in, l, usedAllowances)
+		if err == nil && out.Currency != "" && line.currency != out.Currency {
+			err = fmt.Errorf("SKU %s is priced in %s, other lines in %s", line.SKUID, line.currency, out.Currency)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return EstimateOutput{}, ctx.Err()
+			}
+			out.Unresolved = append(out.Unresolved, UnresolvedLine{Index: i, Name: l.Name, Error: err.Error()})
+			continue
+		}
+		out.Currency = line.currency
+		if line.freeTier != nil {
+			usedAllowances[line.freeTier.Note] += line.freeTier.Covered
+		}
+		line.Index = i
+		out.Lines = append(out.Lines, line.EstimateLine)
+		byService[line.Service] += line.Cost
+		byRegion[line.Region] += line.Cost
+		out.Total += line.Cost
+	}
+	out.ByService = subtotals(byService)
+	out.ByRegion = subtotals(byRegion)
+	return out, nil
+}
+
+type pricedLine struct {
+	EstimateLine
+	currency string
+	freeTier *FreeTier // the allowance deducted, if any
+}
+
+// estimateLine resolves the SKU of l, a line of in, and prices it.
+// usedAllowances holds the quantity of each free allowance, by note, deducted
+// by earlier lines; the caller adds what l deducts once it accepts the line.
+func estimateLine(ctx context.Context, skus SKUStore, catalog CatalogStore, in EstimateInput, l EstimateLineInput, usedAllowances map[string]float64) (pricedLine, error) {
+	months := l.Months
+	if months == 0 {
+		months = 1
+	}
+	if months < 0 {
+		return pricedLine{}, fmt.Errorf("months %v must be positive", months)
+	}
+	if err := validateRegion(ctx, catalog, l.Region); err != nil {
+		return pricedLine{}, err
+	}
+	skuID, err := resolveSKU(ctx, catalog, l)
+	if err != nil {
+		return pricedLine{}, err
+	}
+	calc := CalculateInput{SKUID: skuID, Quantity: l.Quantity, Unit: l.Unit, Region: l.Region, HoursPerMonth: l.HoursPerMonth, MonthHours: in.MonthHours, IgnoreFreeTier: in.IgnoreFreeTier}
+	c, err := calculate(ctx, skus, calc)
+	if err != nil {
+		return pricedLine{}, err
+	}
+	if c.FreeTier != nil && c.FreeTier.Applied {
+		if used := usedAllowances[c.FreeTier.Note]; used > 0 {
+			calc.freeTierUsed = used
+			if c, err = calculate(ctx, skus, calc); err != nil {
+				return pricedLine{}, err
+			}
+		}
+	}
+	service := c.Service
+	if service == "" {
+		service = "unknown"
+	}
+	region := l.Region
+	if region == "" {
+		region = "unspecified"
+	}
+	return pricedLine{
+		EstimateLine: EstimateLine{
+			Name:        l.Name,
+			SKUID:       c.SKUID,
+			Description: c.Description,
+			Service:     service,
+			Region:      region,
+			Quantity:    c.Quantity,
+			UsageUnit:   c.UsageUnit,
+			Months:      months,
+			MonthlyCost: c.Total,
+			Discounts:   c.Discounts,
+			Cost:        c.Total * months,
+		},
+		currency: c.Currency,
+		freeTier: appliedFreeTier(c.FreeTier),
+	}, nil
+}
+
+// appliedFreeTier returns f when its allowance was deducted.
+func appliedFreeTier(f *FreeTier) *FreeTier {
+	if f == nil || !f.Applied {
+		return nil
+	}
+	return f
+}
+
+// resolveSKU returns the SKU ID of l, looking it up by description when no ID
+// is given.
+func resolveSKU(ctx context.Context, catalog CatalogStore, l EstimateLineInput) (string, error) {
+	switch {
+	case l.SKUID != "":
+		return l.SKUID, nil
+	case l.Description == "":
+		return "", errors.New("set sku_id or description")
+	}
+	matches, err := catalog.SearchSKUs(ctx, database.SKUFilter{DescriptionPattern: l.Description, ServiceID: l.ServiceID, Region: l.Region, Limit: 2})
+	if err != nil {
+		return "", err
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no SKU matches description %q; use search to find it", l.Description)
+	case 1:
+		return matches[0].SKUID, nil
+	default:
+		return "", fmt.Errorf("several SKUs match description %q, such as %q and %q; narrow it or set sku_id", l.Description, matches[0].Description, matches[1].Description)
+	}
+}
+
+// subtotals orders costs by decreasing cost, then key.
+func subtotals(costs map[string]float64) []Subtotal {
+	out := make([]Subtotal, 0, len(costs))
+	for k, c := range costs {
+		out = append(out, Subtotal{Key: k, Cost: c})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Cost != out[j].Cost {
+			return out[i].Cost > out[j].Cost
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"mcp-server/internal/database"
+)
+
+func TestEstimateTool(t *testing.T) {
+	us := []string{"us-central1"}
+	run := database.Category{ServiceDisplayName: "Cloud Run"}
+	skus := fakeSKUs{
+		skus: map[string]database.SKU{
+			"cpu":    {SKUID: "cpu", Description: "E2 Instance Core running in Americas", ServiceRegions: us, Category: database.Category{ServiceDisplayName: "Compute Engine"}},
+			"run":    {SKUID: "run", ServiceID: "152E-C115-5142", Description: "CPU Allocation Time", Category: run},
+			"run-eu": {SKUID: "run-eu", ServiceID: "152E-C115-5142", Description: "CPU Allocation Time", Category: run},
+		},
+		prices: map[string]database.PricingInfo{
+			"cpu":    price("cpu", 20_000_000),
+			"run":    {SKUID: "run", CurrencyCode: "USD", UsageUnit: "s", TieredRates: []database.TieredRate{{UnitPrice: database.Money{Nanos: 24_000}}}},
+			"run-eu": {SKUID: "run-eu", CurrencyCode: "USD", UsageUnit: "s", TieredRates: []database.TieredRate{{UnitPrice: database.Money{Nanos: 24_000}}}},
+		},
+	}
+	catalog := &fakeCatalog{regions: testRegions, skus: []database.SKU{skus.skus["cpu"]}}
+	_, handler := estimateTool(skus, catalog)
+
+	_, out, err := handler(context.Background(), nil, EstimateInput{Lines: []EstimateLineInput{
+		{Name: "vm", Description: "E2 Instance Core running in *", Region: "us-central1", Quantity: 1460, Unit: "h", Months: 12},
+		{Name: "api", SKUID: "run", Quantity: 200000},
+		{Name: "worker", SKUID: "run-eu", Quantity: 100000},
+		{Name: "missing", SKUID: "nope", Quantity: 1},
+		{Name: "wrong unit", SKUID: "cpu", Quantity: 1, Unit: "GiBy.mo"},
+	}})
+	if err != nil {
+		t.Fatalf("estimate: %v", err)
+	}
+	if len(out.Lines) != 3 || len(out.Unresolved) != 2 || out.Unresolved[0].Index != 3 || !strings.Contains(out.Unresolved[1].Error, "unit") {
+		t.Fatalf("output = %+v", out)
+	}
+	// The Cloud Run allowance covers 180,000 s of the first line only.
+	if api, worker := out.Lines[1], out.Lines[2]; math.Abs(api.Cost-0.48) > 1e-9 || math.Abs(worker.Cost-2.4) > 1e-9 {
+		t.Fatalf("Cloud Run lines = %+v, %+v", api, worker)
+	}
+	vmCost := 1460 * 0.02 * 12
+	if math.Abs(out.Total-(vmCost+2.88)) > 1e-9 {
+		t.Fatalf("total = %v", out.Total)
+	}
+	if out.ByService[0].Key != "Compute Engine" || len(out.ByService) != 2 || out.ByRegion[0].Key != "us-central1" || out.ByRegion[1].Key != "unspecified" {
+		t.Fatalf("subtotals = %+v, %+v", out.ByService, out.ByRegion)
+	}
+
+	// month_hours sets the month length of every line.
+	_, out, err = handler(context.Background(), nil, EstimateInput{MonthHours: 720, Lines: []EstimateLineInput{{SKUID: "cpu", Quantity: 2, Unit: "mo"}}})
+	if err != nil || len(out.Lines) != 1 || out.Lines[0].Quantity != 1440 {
+		t.Fatalf("output with 720-hour months = %+v, %v", out, err)
+	}
+
+	if _, _, err := handler(context.Background(), nil, EstimateInput{}); err == nil {
+		t.Fatal("empty estimate accepted")
+	}
+}
+
+func TestEstimateTool_SharedFreeAllowance(t *testing.T) {
+	skus := fakeSKUs{
+		skus: map[string]database.SKU{
+			"analysis":     {SKUID: "analysis", ServiceID: "24E6-581D-38E5", Description: "Analysis", Category: database.Category{ServiceDisplayName: "BigQuery"}},
+			"analysis-eur": {SKUID: "analysis-eur", ServiceID: "24E6-581D-38E5", Description: "Analysis", Category: database.Category{ServiceDisplayName: "BigQuery"}},
+		},
+		prices: map[string]database.PricingInfo{
+			"analysis":     {SKUID: "analysis", CurrencyCode: "USD", UsageUnit: "TiBy", TieredRates: []database.TieredRate{{UnitPrice: database.Money{Units: 6, Nanos: 250_000_000}}}},
+			"analysis-eur": {SKUID: "analysis-eur", CurrencyCode: "EUR", UsageUnit: "TiBy", TieredRates: []database.TieredRate{{UnitPrice: database.Money{Units: 6}}}},
+		},
+	}
+	_, handler := estimateTool(skus, &fakeCatalog{regions: testRegions})
+
+	// The 1 TiB allowance covers the first two lines whole and none of the
+	// third. The line priced in euros is rejected and leaves it untouched.
+	_, out, err := handler(context.Background(), nil, EstimateInput{Lines: []EstimateLineInput{
+		{Name: "dashboards", SKUID: "analysis", Quantity: 0.2},
+		{Name: "in euros", SKUID: "analysis-eur", Quantity: 0.8},
+		{Name: "reports", SKUID: "analysis", Quantity: 0.8},
+		{Name: "ad hoc", SKUID: "analysis", Quantity: 0.5},
+	}})
+	if err != nil {
+		t.Fatalf("estimate: %v", err)
+	}
+	if len(out.Lines) != 3 || len(out.Unresolved) != 1 || out.Unresolved[0].Index != 1 {
+		t.Fatalf("output = %+v", out)
+	}
+	for i, want := range []float64{0, 0, 3.125} {
+		if l := out.Lines[i]; math.Abs(l.Cost-want) > 1e-9 {
+			t.Errorf("line %d (%s) cost = %v, want %v", i, l.Name, l.Cost, want)
+		}
+	}
+	if math.Abs(out.Total-3.125) > 1e-9 {
+		t.Fatalf("total = %v, want 3.125", out.Total)
+	}
+}
@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/compute"
+)
+
+// DiskInput is a persistent disk attached to the VM.
+type DiskInput struct {
+	Type   string  `json:"type" jsonschema:"disk type: pd-standard, pd-balanced, pd-ssd or pd-extreme"`
+	SizeGB float64 `json:"size_gb" jsonschema:"disk size in GB"`
+}
+
+// EstimateVMInput describes a Compute Engine VM configuration, either as a
+// predefined machine type or as a family with custom vCPUs and memory.
+type EstimateVMInput struct {
+	MachineType       string      `json:"machine_type,omitempty" jsonschema:"predefined machine type such as n2-standard-8; replaces machine_family, vcpus and memory_gb"`
+	MachineFamily     string      `json:"machine_family,omitempty" jsonschema:"machine family such as n2, e2 or c3"`
+	VCPUs             int         `json:"vcpus,omitempty" jsonschema:"number of vCPUs"`
+	MemoryGB          float64     `json:"memory_gb,omitempty" jsonschema:"memory in GB"`
+	Region            string      `json:"region" jsonschema:"region ID such as us-central1"`
+	HoursPerMonth     float64     `json:"hours_per_month,omitempty" jsonschema:"hours the VM runs per month, default 730"`
+	ProvisioningModel string      `json:"provisioning_model,omitempty" jsonschema:"on-demand (default) or spot"`
+	Disks             []DiskInput `json:"disks,omitempty" jsonschema:"persistent disks, billed for the whole month"`
+}
+
+// VMLine is the monthly cost of one billed resource of the VM.
+type VMLine struct {
+	Item        string  `json:"item"`
+	SKUID       string  `json:"sku_id"`
+	Description string  `json:"description"`
+	Quantity    float64 `json:"quantity"`
+	UsageUnit   string  `json:"usage_unit"`
+	UnitPrice   float64 `json:"unit_price"`
+	Cost        float64 `json:"cost"`
+}
+
+// EstimateVMOutput is the monthly cost of the VM, itemized by resource.
+type EstimateVMOutput struct {
+	MachineType       string   `json:"machine_type,omitempty"`
+	Region            string   `json:"region"`
+	ProvisioningModel string   `json:"provisioning_model"`
+	HoursPerMonth     float64  `json:"hours_per_month"`
+	Currency          string   `json:"currency"`
+	Lines             []VMLine `json:"lines"`
+	Total             float64  `json:"total"`
+}
+
+func estimateVMTool(skus SKUStore, catalog CatalogStore, machineTypes MachineTypeStore) (*sdk.Tool, sdk.ToolHandlerFor[EstimateVMInput, EstimateVMOutput]) {
+	tool := &sdk.Tool{
+		Name: "estimate_vm",
+		Description: "Estimates the monthly cost of a Compute Engine VM from its machine type, or machine family, vCPUs and memory, " +
+			"with its disks and region, itemized by the vCPU, memory, GPU and disk SKUs it is billed under.",
+	}
+	store := struct {
+		CatalogStore
+		SKUStore
+	}{catalog, skus}
+	handler := func(ctx context.Context, req *sdk.CallToolRequest, in EstimateVMInput) (*sdk.CallToolResult, EstimateVMOutput, error) {
+		if in.Region == "" {
+			return nil, EstimateVMOutput{}, errors.New("region is required")
+		}
+		if err := validateRegion(ctx, catalog, in.Region); err != nil {
+			return nil, EstimateVMOutput{}, err
+		}
+		vm := compute.VM{
+			Family:   in.MachineFamily,
+			VCPUs:    in.VCPUs,
+			MemoryGB: in.MemoryGB,
+			Region:   in.Region,
+			Hours:    in.HoursPerMonth,
+		}
+		if in.MachineType != "" {
+			if in.MachineFamily != "" || in.VCPUs != 0 || in.MemoryGB != 0 {
+				return nil, EstimateVMOutput{}, errors.New("set machine_type or machine_family, vcpus and memory_gb, not both")
+			}
+			if machineTypes == nil {
+				return nil, EstimateVMOutput{}, errors.New("machine types are not available, set machine_family, vcpus and memory_gb")
+			}
+			m, err := machineTypeByName(ctx, machineTypes, in.MachineType)
+			if err != nil {
+				return nil, EstimateVMOutput{}, err
+			}
+			vm.Family, vm.VCPUs, vm.BilledVCPUs, vm.MemoryGB = m.Family, m.VCPUs, m.BilledVCPUs, m.MemoryGB
+			vm.GPUs, vm.GPUType, vm.LocalSSDGB = m.GPUs, m.GPUType, m.LocalSSDGB
+		}
+		if vm.Hours == 0 {
+			vm.Hours = compute.HoursPerMonth
+		}
+		model := in.ProvisioningModel
+		switch model {
+		case "":
+			model = "on-demand"
+		case "on-demand":
+		case "spot":
+			vm.Spot = true
+		default:
+			return nil, EstimateVMOutput{}, fmt.Errorf("provisioning_model %q is not on-demand or spot", model)
+		}
+		for _, d := range in.Disks {
+			vm.Disks = append(vm.Disks, compute.Disk(d))
+		}
+		est, err := compute.EstimateVM(ctx, store, vm)
+		if err != nil {
+			return nil, EstimateVMOutput{}, err
+		}
+		out := EstimateVMOutput{
+			MachineType:       in.MachineType,
+			Region:            in.Region,
+			ProvisioningModel: model,
+			HoursPerMonth:     vm.Hours,
+			Currency:          est.Currency,
+			Lines:             make([]VMLine, 0, len(est.Lines)),
+			Total:             est.Total,
+		}
+		for _, l := range est.Lines {
+			out.Lines = append(out.Lines, VMLine(l))
+		}
+		return nil, out, nil
+	}
+	return tool, handler
+}
@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"math"
+	"path"
+	"strings"
+	"testing"
+
+	"mcp-server/internal/database"
+)
+
+// patternCatalog filters SKUs by description pattern, which estimate_vm
+// relies on to tell the vCPU SKU from the memory SKU.
+type patternCatalog struct {
+	*fakeCatalog
+}
+
+func (c patternCatalog) SearchSKUs(ctx context.Context, f database.SKUFilter) ([]database.SKU, error) {
+	var out []database.SKU
+	for _, s := range c.skus {
+		if ok, _ := path.Match(f.DescriptionPattern, s.Description); ok {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func TestEstimateVMTool(t *testing.T) {
+	catalog := patternCatalog{&fakeCatalog{regions: testRegions, skus: []database.SKU{
+		{SKUID: "cpu", Description: "E2 Instance Core running in Americas"},
+		{SKUID: "ram", Description: "E2 Instance Ram running in Americas"},
+		{SKUID: "spot-cpu", Description: "Spot Preemptible E2 Instance Core running in Americas"},
+		{SKUID: "spot-ram", Description: "Spot Preemptible E2 Instance Ram running in Americas"},
+	}}}
+	ram := price("ram", 3_000_000)
+	ram.UsageUnit = "GiBy.h"
+	spotRAM := price("spot-ram", 1_000_000)
+	spotRAM.UsageUnit = "GiBy.h"
+	skus := fakeSKUs{prices: map[string]database.PricingInfo{
+		"cpu":      price("cpu", 20_000_000),
+		"ram":      ram,
+		"spot-cpu": price("spot-cpu", 5_000_000),
+		"spot-ram": spotRAM,
+	}}
+	_, handler := estimateVMTool(skus, catalog, &fakeMachineTypes{types: testMachineTypes})
+	ctx := context.Background()
+
+	_, out, err := handler(ctx, nil, EstimateVMInput{MachineFamily: "e2", VCPUs: 2, MemoryGB: 8, Region: "us-central1"})
+	if err != nil {
+		t.Fatalf("estimate: %v", err)
+	}
+	// 2*730*0.02 + 8*730*0.003
+	if out.HoursPerMonth != 730 || out.ProvisioningModel != "on-demand" || len(out.Lines) != 2 || math.Abs(out.Total-(29.2+17.52)) > 1e-9 {
+		t.Fatalf("output = %+v", out)
+	}
+
+	_, out, err = handler(ctx, nil, EstimateVMInput{MachineFamily: "e2", VCPUs: 2, MemoryGB: 8, Region: "us-central1", ProvisioningModel: "spot", HoursPerMonth: 100})
+	if err != nil || out.Lines[0].SKUID != "spot-cpu" || math.Abs(out.Total-(1+0.8)) > 1e-9 {
+		t.Fatalf("spot output = %+v, %v", out, err)
+	}
+
+	_, out, err = handler(ctx, nil, EstimateVMInput{MachineType: "e2-standard-2", Region: "us-central1"})
+	if err != nil || out.MachineType != "e2-standard-2" || math.Abs(out.Total-(29.2+17.52)) > 1e-9 {
+		t.Fatalf("machine type output = %+v, %v", out, err)
+	}
+	// e2-micro bills a quarter of its two vCPUs: 0.25*730*0.02 + 1*730*0.003.
+	_, out, err = handler(ctx, nil, EstimateVMInput{MachineType: "e2-micro", Region: "us-central1"})
+	if err != nil || out.Lines[0].Quantity != 182.5 || math.Abs(out.Total-(3.65+2.19)) > 1e-9 {
+		t.Fatalf("shared-core output = %+v, %v", out, err)
+	}
+
+	for _, tt := range []struct {
+		in   EstimateVMInput
+		want string
+	}{
+		{EstimateVMInput{MachineFamily: "e2", VCPUs: 2, MemoryGB: 8}, "region is required"},
+		{EstimateVMInput{MachineFamily: "e2", VCPUs: 2, MemoryGB: 8, Region: "moon-1"}, "unknown region"},
+		{EstimateVMInput{MachineFamily: "e2", VCPUs: 2, MemoryGB: 8, Region: "us-central1", ProvisioningModel: "reserved"}, "provisioning_model"},
+		{EstimateVMInput{MachineFamily: "n2", VCPUs: 2, MemoryGB: 8, Region: "us-central1"}, "no SKU matching"},
+		{EstimateVMInput{MachineType: "e2-mega-1", Region: "us-central1"}, "list_machine_types"},
+		{EstimateVMInput{MachineType: "e2-standard-2", VCPUs: 4, Region: "us-central1"}, "not both"},
+	} {
+		if _, _, err := handler(ctx, nil, tt.in); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("estimate %+v: got %v, want error containing %q", tt.in, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,411 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/database"
+)
+
+// shareTokenPrefix marks read-only estimate share tokens.
+const shareTokenPrefix = "cpe_"
+
+// EstimateStore persists saved estimates.
+type EstimateStore interface {
+	InsertEstimate(ctx context.Context, e database.Estimate) error
+	EstimateByID(ctx context.Context, estimateID string) (database.Estimate, error)
+	EstimateByShareHash(ctx context.Context, hash string) (database.Estimate, error)
+	ListEstimates(ctx context.Context, owner string) ([]database.Estimate, error)
+	UpdateEstimatePricing(ctx context.Context, estimateID, currency string, total float64, at time.Time) error
+	SetEstimateShareHash(ctx context.Context, estimateID, owner, hash string) error
+	DeleteEstimate(ctx context.Context, estimateID, owner string) error
+	InsertEstimateDrift(ctx context.Context, d database.EstimateDrift) error
+	ListEstimateDrift(ctx context.Context, f database.EstimateDriftFilter) ([]database.EstimateDrift, error)
+}
+
+var _ EstimateStore = (*database.SQLRepository)(nil)
+
+// errEstimateNotFound is returned for missing estimates and those of other
+// principals alike, so estimate IDs cannot be probed.
+var errEstimateNotFound = errors.New("estimate not found; call list_estimates for your saved estimates")
+
+// owner returns the principal saved estimates of req belong to.
+func owner(req *sdk.CallToolRequest) (string, error) {
+	p, ok := principalFromRequest(req)
+	if !ok {
+		return "", errors.New("saved estimates require an authenticated principal")
+	}
+	return p.String(), nil
+}
+
+// newShareToken returns a random share token and its hash.
+func newShareToken() (token, hash string, err error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = shareTokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return token, hashShareToken(token), nil
+}
+
+// hashShareToken returns the hex-encoded SHA-256 hash of token; only hashes
+// are stored.
+func hashShareToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// newEstimateID returns a random estimate ID.
+func newEstimateID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "est_" + hex.EncodeToString(b), nil
+}
+
+// EstimateRef names a saved estimate by ID, for its owner, or by share token,
+// for anyone holding it.
+type EstimateRef struct {
+	EstimateID string `json:"estimate_id,omitempty" jsonschema:"ID of one of your saved estimates"`
+	ShareToken string `json:"share_token,omitempty" jsonschema:"read-only share token of an estimate, alternatively to estimate_id"`
+}
+
+// lookupEstimate returns the estimate ref names and whether the caller owns
+// it.
+func lookupEstimate(ctx context.Context, store EstimateStore, req *sdk.CallToolRequest, ref EstimateRef) (database.Estimate, bool, error) {
+	var (
+		e   database.Estimate
+		err error
+	)
+	switch {
+	case ref.EstimateID != "" && ref.ShareToken != "":
+		return database.Estimate{}, false, errors.New("set estimate_id or share_token, not both")
+	case ref.ShareToken != "":
+		if !strings.HasPrefix(ref.ShareToken, shareTokenPrefix) {
+			return database.Estimate{}, false, errors.New("invalid share token")
+		}
+		e, err = store.EstimateByShareHash(ctx, hashShareToken(ref.ShareToken))
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.Estimate{}, false, errors.New("invalid or revoked share token")
+		}
+	case ref.EstimateID != "":
+		e, err = store.EstimateByID(ctx, ref.EstimateID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.Estimate{}, false, errEstimateNotFound
+		}
+	default:
+		return database.Estimate{}, false, errors.New("estimate_id or share_token is required")
+	}
+	if err != nil {
+		return database.Estimate{}, false, err
+	}
+	me, _ := owner(req)
+	if ref.EstimateID != "" && e.Owner != me {
+		return database.Estimate{}, false, errEstimateNotFound
+	}
+	return e, e.Owner == me, nil
+}
+
+// SaveEstimateInput is a bill of materials to price and save.
+type SaveEstimateInput struct {
+	Name string `json:"name" jsonschema:"name of the estimate, such as checkout service production"`
+	EstimateInput
+	Share bool `json:"share,omitempty" jsonschema:"also create a read-only share token"`
+}
+
+// SaveEstimateOutput is the saved estimate as priced now.
+type SaveEstimateOutput struct {
+	EstimateID string         `json:"estimate_id"`
+	Name       string         `json:"name"`
+	ShareToken string         `json:"share_token,omitempty"`
+	Estimate   EstimateOutput `json:"estimate"`
+}
+
+func saveEstimateTool(skus SKUStore, catalog CatalogStore, store EstimateStore) (*sdk.Tool, sdk.ToolHandlerFor[SaveEstimateInput, SaveEstimateOutput]) {
+	tool := &sdk.Tool{
+		Name: "save_estimate",
+		Description: "Prices a bill of materials like the estimate tool and saves it under your identity, " +
+			"returning an ID to load, re-price or delete it later and, on request, a read-only share token. " +
+			"Every line must resolve for the estimate to be saved.",
+	}
+	handler := func(ctx context.Context, req *sdk.CallToolRequest, in SaveEstimateInput) (*sdk.CallToolResult, SaveEstimateOutput, error) {
+		me, err := owner(req)
+		if err != nil {
+			return nil, SaveEstimateOutput{}, err
+		}
+		if strings.TrimSpace(in.Name) == "" {
+			return nil, SaveEstimateOutput{}, errors.New("name is required")
+		}
+		priced, err := estimate(ctx, skus, catalog, in.EstimateInput)
+		if err != nil {
+			return nil, SaveEstimateOutput{}, err
+		}
+		if err := unresolvedError(priced); err != nil {
+			return nil, SaveEstimateOutput{}, err
+		}
+		input, err := json.Marshal(in.EstimateInput)
+		if err != nil {
+			return nil, SaveEstimateOutput{}, err
+		}
+		id, err := newEstimateID()
+		if err != nil {
+			return nil, SaveEstimateOutput{}, err
+		}
+		out := SaveEstimateOutput{EstimateID: id, Name: in.Name, Estimate: priced}
+		var shareHash string
+		if in.Share {
+			if out.ShareToken, shareHash, err = newShareToken(); err != nil {
+				return nil, SaveEstimateOutput{}, err
+			}
+		}
+		now := time.Now().UTC()
+		err = store.InsertEstimate(ctx, database.Estimate{
+			EstimateID:     id,
+			Owner:          me,
+			Name:           in.Name,
+			Input:          input,
+			Currency:       priced.Currency,
+			Total:          priced.Total,
+			ShareTokenHash: shareHash,
+			CreatedAt:      now,
+			PricedAt:       now,
+		})
+		if err != nil {
+			return nil, SaveEstimateOutput{}, err
+		}
+		return nil, out, nil
+	}
+	return tool, handler
+}
+
+// EstimateSummary describes a saved estimate.
+type EstimateSummary struct {
+	EstimateID string    `json:"estimate_id"`
+	Name       string    `json:"name"`
+	Currency   string    `json:"currency"`
+	Total      float64   `json:"total"`
+	Shared     bool      `json:"shared"`
+	CreatedAt  time.Time `json:"created_at"`
+	PricedAt   time.Time `json:"priced_at"`
+}
+
+func summarize(e database.Estimate) EstimateSummary {
+	return EstimateSummary{
+		EstimateID: e.EstimateID,
+		Name:       e.Name,
+		Currency:   e.Currency,
+		Total:      e.Total,
+		Shared:     e.ShareTokenHash != "",
+		CreatedAt:  e.CreatedAt,
+		PricedAt:   e.PricedAt,
+	}
+}
+
+// ListEstimatesInput takes no arguments.
+type ListEstimatesInput struct{}
+
+// ListEstimatesOutput lists your saved estimates, newest first.
+type ListEstimatesOutput struct {
+	Estimates []EstimateSummary `json:"estimates"`
+}
+
+func listEstimatesTool(store EstimateStore) (*sdk.Tool, sdk.ToolHandlerFor[ListEstimatesInput, ListEstimatesOutput]) {
+	tool := &sdk.Tool{
+		Name:        "list_estimates",
+		Description: "Lists your saved estimates with their totals when last priced.",
+	}
+	handler := func(ctx context.Context, req *sdk.CallToolRequest, in ListEstimatesInput) (*sdk.CallToolResult, ListEstimatesOutput, error) {
+		me, err := owner(req)
+		if err != nil {
+			return nil, ListEstimatesOutput{}, err
+		}
+		estimates, err := store.ListEstimates(ctx, me)
+		if err != nil {
+			return nil, ListEstimatesOutput{}, err
+		}
+		out := ListEstimatesOutput{Estimates: make([]EstimateSummary, 0, len(estimates))}
+		for _, e := range estimates {
+			out.Estimates = append(out.Estimates, summarize(e))
+		}
+		return nil, out, nil
+	}
+	return tool, handler
+}
+
+// LoadEstimateOutput is a saved estimate with its line items.
+type LoadEstimateOutput struct {
+	EstimateSummary
+	Owner    string        `json:"owner"`
+	ReadOnly bool          `json:"read_only"`
+	Input    EstimateInput `json:"input"`
+}
+
+func loadEstimateTool(store EstimateStore) (*sdk.Tool, sdk.ToolHandlerFor[EstimateRef, LoadEstimateOutput]) {
+	tool := &sdk.Tool{
+		Name:        "load_estimate",
+		Description: "Loads a saved estimate, by ID or share token, with its line items and the total when last priced.",
+	}
+	handler := func(ctx context.Context, req *sdk.CallToolRequest, in EstimateRef) (*sdk.CallToolResult, LoadEstimateOutput, error) {
+		e, mine, err := lookupEstimate(ctx, store, req, in)
+		if err != nil {
+			return nil, LoadEstimateOutput{}, err
+		}
+		out := LoadEstimateOutput{EstimateSummary: summarize(e), Owner: e.Owner, ReadOnly: !mine}
+		if err := json.Unmarshal(e.Input, &out.Input); err != nil {
+			return nil, LoadEstimateOutput{}, fmt.Errorf("decode estimate %s: %w", e.EstimateID, err)
+		}
+		return nil, out, nil
+	}
+	return tool, handler
+}
+
+// RepriceEstimateOutput is a saved estimate priced against current pricing.
+type RepriceEstimateOutput struct {
+	EstimateID    string         `json:"estimate_id"`
+	Name          string         `json:"name"`
+	PreviousTotal float64        `json:"previous_total"`
+	PricedAt      time.Time      `json:"previously_priced_at"`
+	Delta         float64        `json:"delta"`
+	DeltaPercent  float64        `json:"delta_percent"`
+	Saved         bool           `json:"saved"`
+	Estimate      EstimateOutput `json:"estimate"`
+}
+
+func repriceEstimateTool(skus SKUStore, catalog CatalogStore, store EstimateStore) (*sdk.Tool, sdk.ToolHandlerFor[EstimateRef, RepriceEstimateOutput]) {
+	tool := &sdk.Tool{
+		Name: "reprice_estimate",
+		Description: "Prices a saved estimate against current pricing and reports the change since it was last priced. " +
+			"The new total is saved for your own estimates, not for those opened with a share token, " +
+			"nor while some lines no longer resolve.",
+	}
+	handler := func(ctx context.Context, req *sdk.CallToolRequest, in EstimateRef) (*sdk.CallToolResult, RepriceEstimateOutput, error) {
+		e, mine, err := lookupEstimate(ctx, store, req, in)
+		if err != nil {
+			return nil, RepriceEstimateOutput{}, err
+		}
+		var input EstimateInput
+		if err := json.Unmarshal(e.Input, &input); err != nil {
+			return nil, RepriceEstimateOutput{}, fmt.Errorf("decode estimate %s: %w", e.EstimateID, err)
+		}
+		priced, err := estimate(ctx, skus, catalog, input)
+		if err != nil {
+			return nil, RepriceEstimateOutput{}, err
+		}
+		out := RepriceEstimateOutput{
+			EstimateID:    e.EstimateID,
+			Name:          e.Name,
+			PreviousTotal: e.Total,
+			PricedAt:      e.PricedAt,
+			Delta:         priced.Total - e.Total,
+			DeltaPercent:  percentChange(e.Total, priced.Total),
+			Estimate:      priced,
+		}
+		if err := unresolvedError(priced); err != nil {
+			slog.WarnContext(ctx, "reprice estimate", "estimate_id", e.EstimateID, "error", err)
+		} else if mine {
+			if _, err := savePricing(ctx, store, e, priced, time.Now().UTC()); err != nil {
+				return nil, RepriceEstimateOutput{}, err
+			}
+			out.Saved = true
+		}
+		return nil, out, nil
+	}
+	return tool, handler
+}
+
+// ShareEstimateInput creates or revokes the share token of an estimate.
+type ShareEstimateInput struct {
+	EstimateID string `json:"estimate_id" jsonschema:"ID of one of your saved estimates"`
+	Revoke     bool   `json:"revoke,omitempty" jsonschema:"revoke the share token instead of creating one"`
+}
+
+// ShareEstimateOutput holds the new share token, which replaces any earlier
+// one.
+type ShareEstimateOutput struct {
+	EstimateID string `json:"estimate_id"`
+	ShareToken string `json:"share_token,omitempty"`
+	Shared     bool   `json:"shared"`
+}
+
+func shareEstimateTool(store EstimateStore) (*sdk.Tool, sdk.ToolHandlerFor[ShareEstimateInput, ShareEstimateOutput]) {
+	tool := &sdk.Tool{
+		Name: "share_estimate",
+		Description: "Creates a read-only share token for one of your saved estimates, replacing any earlier token, or revokes it. " +
+			"Anyone holding the token can load and re-price the estimate but not change or delete it.",
+	}
+	handler := func(ctx context.Context, req *sdk.CallToolRequest, in ShareEstimateInput) (*sdk.CallToolResult, ShareEstimateOutput, error) {
+		me, err := owner(req)
+		if err != nil {
+			return nil, ShareEstimateOutput{}, err
+		}
+		if in.EstimateID == "" {
+			return nil, ShareEstimateOutput{}, errors.New("estimate_id is required")
+		}
+		out := ShareEstimateOutput{EstimateID: in.EstimateID}
+		var hash string
+		if !in.Revoke {
+			if out.ShareToken, hash, err = newShareToken(); err != nil {
+				return nil, ShareEstimateOutput{}, err
+			}
+			out.Shared = true
+		}
+		err = store.SetEstimateShareHash(ctx, in.EstimateID, me, hash)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ShareEstimateOutput{}, errEstimateNotFound
+		}
+		if err != nil {
+			return nil, ShareEstimateOutput{}, err
+		}
+		return nil, out, nil
+	}
+	return tool, handler
+}
+
+// DeleteEstimateInput names the estimate to delete.
+type DeleteEstimateInput struct {
+	EstimateID string `json:"estimate_id" jsonschema:"ID of one of your saved estimates"`
+}
+
+// DeleteEstimateOutput confirms the deletion.
+type DeleteEstimateOutput struct {
+	EstimateID string `json:"estimate_id"`
+	Deleted    bool   `json:"deleted"`
+}
+
+func deleteEstimateTool(store EstimateStore) (*sdk.Tool, sdk.ToolHandlerFor[DeleteEstimateInput, DeleteEstimateOutput]) {
+	tool := &sdk.Tool{
+		Name:        "delete_estimate",
+		Description: "Deletes one of your saved estimates and its share token.",
+	}
+	handler := func(ctx context.Context, req *sdk.CallToolRequest, in DeleteEstimateInput) (*sdk.CallToolResult, DeleteEstimateOutput, error) {
+		me, err := owner(req)
+		if err != nil {
+			return nil, DeleteEstimateOutput{}, err
+		}
+		if in.EstimateID == "" {
+			return nil, DeleteEstimateOutput{}, errors.New("estimate_id is required")
+		}
+		err = store.DeleteEstimate(ctx, in.EstimateID, me)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, DeleteEstimateOutput{}, errEstimateNotFound
+		}
+		if err != nil {
+			return nil, DeleteEstimateOutput{}, err
+		}
+		return nil, DeleteEstimateOutput{EstimateID: in.EstimateID, Deleted: true}, nil
+	}
+	return tool, handler
+}
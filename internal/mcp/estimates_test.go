@@ -0,0 +1,205 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	sdkauth "github.com/modelcontextprotocol/go-sdk/auth"
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/auth"
+	"mcp-server/internal/database"
+)
+
+type fakeEstimates struct {
+	byID  map[string]database.Estimate
+	drift []database.EstimateDrift // oldest first
+}
+
+func newFakeEstimates() *fakeEstimates {
+	return &fakeEstimates{byID: map[string]database.Estimate{}}
+}
+
+func (f *fakeEstimates) InsertEstimate(ctx context.Context, e database.Estimate) error {
+	f.byID[e.EstimateID] = e
+	return nil
+}
+
+func (f *fakeEstimates) EstimateByID(ctx context.Context, id string) (database.Estimate, error) {
+	e, ok := f.byID[id]
+	if !ok {
+		return database.Estimate{}, sql.ErrNoRows
+	}
+	return e, nil
+}
+
+func (f *fakeEstimates) EstimateByShareHash(ctx context.Context, hash string) (database.Estimate, error) {
+	for _, e := range f.byID {
+		if e.ShareTokenHash == hash {
+			return e, nil
+		}
+	}
+	return database.Estimate{}, sql.ErrNoRows
+}
+
+func (f *fakeEstimates) ListEstimates(ctx context.Context, owner string) ([]database.Estimate, error) {
+	var out []database.Estimate
+	for _, e := range f.byID {
+		if owner == "" || e.Owner == owner {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeEstimates) UpdateEstimatePricing(ctx context.Context, id, currency string, total float64, at time.Time) error {
+	e, ok := f.byID[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	e.Currency, e.Total, e.PricedAt = currency, total, at
+	f.byID[id] = e
+	return nil
+}
+
+func (f *fakeEstimates) SetEstimateShareHash(ctx context.Context, id, owner, hash string) error {
+	e, ok := f.byID[id]
+	if !ok || e.Owner != owner {
+		return sql.ErrNoRows
+	}
+	e.ShareTokenHash = hash
+	f.byID[id] = e
+	return nil
+}
+
+func (f *fakeEstimates) DeleteEstimate(ctx context.Context, id, owner string) error {
+	if e, ok := f.byID[id]; !ok || e.Owner != owner {
+		return sql.ErrNoRows
+	}
+	delete(f.byID, id)
+	return nil
+}
+
+func (f *fakeEstimates) InsertEstimateDrift(ctx context.Context, d database.EstimateDrift) error {
+	f.drift = append(f.drift, d)
+	return nil
+}
+
+func (f *fakeEstimates) ListEstimateDrift(ctx context.Context, filter database.EstimateDriftFilter) ([]database.EstimateDrift, error) {
+	var out []database.EstimateDrift
+	for i := len(f.drift) - 1; i >= 0; i-- {
+		d := f.drift[i]
+		e, ok := f.byID[d.EstimateID]
+		if !ok || filter.Owner != "" && e.Owner != filter.Owner || filter.EstimateID != "" && d.EstimateID != filter.EstimateID || d.PricedAt.Before(filter.Since) {
+			continue
+		}
+		d.Name = e.Name
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+// callAs returns a tool call request made by the GitHub user login.
+func callAs(login string) *sdk.CallToolRequest {
+	p := auth.Principal{Login: login, Role: auth.RoleUser}
+	return &sdk.CallToolRequest{Extra: &sdk.RequestExtra{TokenInfo: &sdkauth.TokenInfo{Extra: map[string]any{principalExtraKey: p}}}}
+}
+
+func TestSavedEstimateTools(t *testing.T) {
+	skus := fakeSKUs{
+		skus:   map[string]database.SKU{"cpu": {SKUID: "cpu", Description: "E2 Instance Core running in Americas"}},
+		prices: map[string]database.PricingInfo{"cpu": price("cpu", 20_000_000)},
+	}
+	catalog := &fakeCatalog{regions: testRegions}
+	store := newFakeEstimates()
+	_, save := saveEstimateTool(skus, catalog, store)
+	_, list := listEstimatesTool(store)
+	_, load := loadEstimateTool(store)
+	_, reprice := repriceEstimateTool(skus, catalog, store)
+	_, share := shareEstimateTool(store)
+	_, del := deleteEstimateTool(store)
+	ctx := context.Background()
+	alice, bob := callAs("alice"), callAs("bob")
+
+	_, saved, err := save(ctx, alice, SaveEstimateInput{Name: "web", EstimateInput: EstimateInput{Lines: []EstimateLineInput{{SKUID: "cpu", Quantity: 730}}}, Share: true})
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if !strings.HasPrefix(saved.EstimateID, "est_") || !strings.HasPrefix(saved.ShareToken, shareTokenPrefix) || math.Abs(saved.Estimate.Total-14.6) > 1e-9 {
+		t.Fatalf("saved = %+v", saved)
+	}
+	if stored := store.byID[saved.EstimateID]; stored.Owner != "github:alice" || stored.ShareTokenHash == saved.ShareToken {
+		t.Fatalf("stored = %+v", stored)
+	}
+	// A total leaving out a line would show as drift once the line resolves.
+	partial := EstimateInput{Lines: []EstimateLineInput{{SKUID: "cpu", Quantity: 730}, {SKUID: "nope", Quantity: 1}}}
+	if _, _, err := save(ctx, alice, SaveEstimateInput{Name: "partial", EstimateInput: partial}); err == nil || !strings.Contains(err.Error(), "1 of 2 lines do not resolve: line 1:") {
+		t.Fatalf("saving with an unresolved line: got %v", err)
+	}
+	if len(store.byID) != 1 {
+		t.Fatalf("stored after a rejected save = %+v", store.byID)
+	}
+
+	if _, out, err := list(ctx, alice, ListEstimatesInput{}); err != nil || len(out.Estimates) != 1 || !out.Estimates[0].Shared {
+		t.Fatalf("alice's estimates = %+v, %v", out, err)
+	}
+	if _, out, err := list(ctx, bob, ListEstimatesInput{}); err != nil || len(out.Estimates) != 0 {
+		t.Fatalf("bob's estimates = %+v, %v", out, err)
+	}
+
+	_, loaded, err := load(ctx, alice, EstimateRef{EstimateID: saved.EstimateID})
+	if err != nil || loaded.ReadOnly || len(loaded.Input.Lines) != 1 || loaded.Input.Lines[0].SKUID != "cpu" {
+		t.Fatalf("load = %+v, %v", loaded, err)
+	}
+	if _, _, err := load(ctx, bob, EstimateRef{EstimateID: saved.EstimateID}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("bob loading by ID: got %v", err)
+	}
+	if _, shared, err := load(ctx, bob, EstimateRef{ShareToken: saved.ShareToken}); err != nil || !shared.ReadOnly || shared.Owner != "github:alice" {
+		t.Fatalf("bob loading by share token = %+v, %v", shared, err)
+	}
+
+	// The price goes up 10%; bob sees it through the token without saving it.
+	skus.prices["cpu"] = price("cpu", 22_000_000)
+	_, repriced, err := reprice(ctx, bob, EstimateRef{ShareToken: saved.ShareToken})
+	if err != nil || repriced.Saved || math.Abs(repriced.DeltaPercent-10) > 1e-9 {
+		t.Fatalf("bob repricing = %+v, %v", repriced, err)
+	}
+	_, repriced, err = reprice(ctx, alice, EstimateRef{EstimateID: saved.EstimateID})
+	if err != nil || !repriced.Saved || math.Abs(store.byID[saved.EstimateID].Total-16.06) > 1e-9 {
+		t.Fatalf("alice repricing = %+v, %v", repriced, err)
+	}
+	if len(store.drift) != 1 || store.drift[0].PreviousTotal != saved.Estimate.Total {
+		t.Fatalf("drift after repricing = %+v", store.drift)
+	}
+	// Once the SKU is gone the estimate prices at nothing, which is not saved.
+	delete(skus.prices, "cpu")
+	_, repriced, err = reprice(ctx, alice, EstimateRef{EstimateID: saved.EstimateID})
+	if err != nil || repriced.Saved || len(repriced.Estimate.Unresolved) != 1 || math.Abs(store.byID[saved.EstimateID].Total-16.06) > 1e-9 || len(store.drift) != 1 {
+		t.Fatalf("repricing without the SKU = %+v, %v; drift %+v", repriced, err, store.drift)
+	}
+
+	if _, _, err := share(ctx, bob, ShareEstimateInput{EstimateID: saved.EstimateID, Revoke: true}); err == nil {
+		t.Fatal("bob revoked alice's share token")
+	}
+	if _, out, err := share(ctx, alice, ShareEstimateInput{EstimateID: saved.EstimateID, Revoke: true}); err != nil || out.Shared {
+		t.Fatalf("revoke = %+v, %v", out, err)
+	}
+	if _, _, err := load(ctx, bob, EstimateRef{ShareToken: saved.ShareToken}); err == nil {
+		t.Fatal("revoked share token still works")
+	}
+
+	if _, _, err := del(ctx, bob, DeleteEstimateInput{EstimateID: saved.EstimateID}); err == nil {
+		t.Fatal("bob deleted alice's estimate")
+	}
+	if _, out, err := del(ctx, alice, DeleteEstimateInput{EstimateID: saved.EstimateID}); err != nil || !out.Deleted || len(store.byID) != 0 {
+		t.Fatalf("delete = %+v, %v", out, err)
+	}
+
+	if _, _, err := save(ctx, &sdk.CallToolRequest{}, SaveEstimateInput{Name: "anon"}); err == nil || !strings.Contains(err.Error(), "authenticated") {
+		t.Fatalf("anonymous save: got %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestInFlight_Wait(t *testing.T) {
+	f := &InFlight{}
+	if err := f.Wait(context.Background()); err != nil {
+		t.Fatalf("idle wait: %v", err)
+	}
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	h := f.Middleware(func(ctx context.Context, method string, req sdk.Request) (sdk.Result, error) {
+		close(started)
+		<-release
+		return &sdk.CallToolResult{}, nil
+	})
+	go h(context.Background(), "tools/call", callRequest(t, "slow", `{}`))
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := f.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("wait with call running: got %v, want deadline exceeded", err)
+	}
+
+	close(release)
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := f.Wait(ctx); err != nil {
+		t.Fatalf("wait after call finished: %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+package mcp
+
+import (
+	"context"
+	"log/slog"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/logging"
+)
+
+// LogContext attaches the MCP session ID and the ID of the HTTP request that
+// carried the message to the context, so every line logged while handling it
+// can be correlated. Handlers of streamable sessions run in the session's
+// context, so the request ID is read from the header set by
+// logging.Middleware.
+func LogContext(next sdk.MethodHandler) sdk.MethodHandler {
+	return func(ctx context.Context, method string, req sdk.Request) (sdk.Result, error) {
+		var attrs []slog.Attr
+		if extra := req.GetExtra(); extra != nil && extra.Header != nil {
+			if id := extra.Header.Get(logging.RequestIDHeader); id != "" {
+				attrs = append(attrs, slog.String(logging.KeyRequestID, id))
+			}
+		}
+		if ss, ok := req.GetSession().(*sdk.ServerSession); ok && ss != nil && ss.ID() != "" {
+			attrs = append(attrs, slog.String(logging.KeySessionID, ss.ID()))
+		}
+		if len(attrs) > 0 {
+			ctx = logging.With(ctx, attrs...)
+		}
+		return next(ctx, method, req)
+	}
+}
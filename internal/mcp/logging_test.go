@@ -0,0 +1,34 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/logging"
+)
+
+func TestLogContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, "info")
+	h := LogContext(func(ctx context.Context, method string, req sdk.Request) (sdk.Result, error) {
+		logger.InfoContext(ctx, "tool called")
+		return &sdk.CallToolResult{}, nil
+	})
+	req := callRequest(t, "search", `{}`)
+	req.Extra.Header = http.Header{logging.RequestIDHeader: {"req-1"}}
+	if _, err := h(context.Background(), "tools/call", req); err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("decode %q: %v", buf.String(), err)
+	}
+	if line[logging.KeyRequestID] != "req-1" {
+		t.Fatalf("line = %v", line)
+	}
+}
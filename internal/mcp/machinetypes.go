@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/database"
+)
+
+// MachineTypeStore reads the predefined machine types.
+type MachineTypeStore interface {
+	MachineTypeByName(ctx context.Context, name string) (database.MachineType, error)
+	ListMachineTypes(ctx context.Context, f database.MachineTypeFilter) ([]database.MachineType, error)
+}
+
+var _ MachineTypeStore = (*database.SQLRepository)(nil)
+
+// machineTypeByName returns the machine type called name, with an error
+// pointing at list_machine_types when it is unknown.
+func machineTypeByName(ctx context.Context, store MachineTypeStore, name string) (database.MachineType, error) {
+	m, err := store.MachineTypeByName(ctx, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.MachineType{}, fmt.Errorf("unknown machine type %q; call list_machine_types for valid names such as n2-standard-8", name)
+	}
+	return m, err
+}
+
+// ListMachineTypesInput filters machine types.
+type ListMachineTypesInput struct {
+	Family      string  `json:"family,omitempty" jsonschema:"machine family such as n2 or e2"`
+	MinVCPUs    int     `json:"min_vcpus,omitempty" jsonschema:"minimum number of vCPUs"`
+	MaxVCPUs    int     `json:"max_vcpus,omitempty" jsonschema:"maximum number of vCPUs"`
+	MinMemoryGB float64 `json:"min_memory_gb,omitempty" jsonschema:"minimum memory in GB"`
+	MaxMemoryGB float64 `json:"max_memory_gb,omitempty" jsonschema:"maximum memory in GB"`
+}
+
+// MachineTypeInfo describes a predefined machine type.
+type MachineTypeInfo struct {
+	Name   string `json:"name"`
+	Family string `json:"family"`
+	VCPUs  int    `json:"vcpus"`
+	// BilledVCPUs is below VCPUs for shared-core types.
+	BilledVCPUs float64 `json:"billed_vcpus"`
+	MemoryGB    float64 `json:"memory_gb"`
+	GPUs        int     `json:"gpus,omitempty"`
+	GPUType     string  `json:"gpu_type,omitempty"`
+	LocalSSDGB  float64 `json:"local_ssd_gb,omitempty"`
+}
+
+// ListMachineTypesOutput lists machine types ordered by family, vCPUs and
+// memory.
+type ListMachineTypesOutput struct {
+	DatasetVersion string            `json:"dataset_version,omitempty"`
+	MachineTypes   []MachineTypeInfo `json:"machine_types"`
+}
+
+func listMachineTypesTool(store MachineTypeStore) (*sdk.Tool, sdk.ToolHandlerFor[ListMachineTypesInput, ListMachineTypesOutput]) {
+	tool := &sdk.Tool{
+		Name:        "list_machine_types",
+		Description: "Lists predefined Compute Engine machine types, such as n2-standard-8, with their vCPUs, memory, GPUs and local SSD.",
+	}
+	handler := func(ctx context.Context, req *sdk.CallToolRequest, in ListMachineTypesInput) (*sdk.CallToolResult, ListMachineTypesOutput, error) {
+		types, err := store.ListMachineTypes(ctx, database.MachineTypeFilter{
+			Family:      in.Family,
+			MinVCPUs:    in.MinVCPUs,
+			MaxVCPUs:    in.MaxVCPUs,
+			MinMemoryGB: in.MinMemoryGB,
+			MaxMemoryGB: in.MaxMemoryGB,
+		})
+		if err != nil {
+			return nil, ListMachineTypesOutput{}, err
+		}
+		out := ListMachineTypesOutput{MachineTypes: make([]MachineTypeInfo, 0, len(types))}
+		for _, m := range types {
+			out.DatasetVersion = m.Version
+			out.MachineTypes = append(out.MachineTypes, MachineTypeInfo{
+				Name:        m.Name,
+				Family:      m.Family,
+				VCPUs:       m.VCPUs,
+				BilledVCPUs: m.BilledVCPUs,
+				MemoryGB:    m.MemoryGB,
+				GPUs:        m.GPUs,
+				GPUType:     m.GPUType,
+				LocalSSDGB:  m.LocalSSDGB,
+			})
+		}
+		return nil, out, nil
+	}
+	return tool, handler
+}
@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"mcp-server/internal/database"
+)
+
+type fakeMachineTypes struct {
+	types  []database.MachineType
+	filter database.MachineTypeFilter
+}
+
+func (f *fakeMachineTypes) MachineTypeByName(ctx context.Context, name string) (database.MachineType, error) {
+	for _, m := range f.types {
+		if m.Name == name {
+			return m, nil
+		}
+	}
+	return database.MachineType{}, sql.ErrNoRows
+}
+
+func (f *fakeMachineTypes) ListMachineTypes(ctx context.Context, filter database.MachineTypeFilter) ([]database.MachineType, error) {
+	f.filter = filter
+	return f.types, nil
+}
+
+var testMachineTypes = []database.MachineType{
+	{Name: "e2-micro", Family: "e2", VCPUs: 2, BilledVCPUs: 0.25, MemoryGB: 1, Version: "2026-10-01"},
+	{Name: "e2-standard-2", Family: "e2", VCPUs: 2, BilledVCPUs: 2, MemoryGB: 8, Version: "2026-10-01"},
+	{Name: "e2-standard-4", Family: "e2", VCPUs: 4, BilledVCPUs: 4, MemoryGB: 16, Version: "2026-10-01"},
+}
+
+func TestListMachineTypesTool(t *testing.T) {
+	store := &fakeMachineTypes{types: testMachineTypes}
+	_, handler := listMachineTypesTool(store)
+	_, out, err := handler(context.Background(), nil, ListMachineTypesInput{Family: "e2", MinVCPUs: 2})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if store.filter.Family != "e2" || store.filter.MinVCPUs != 2 {
+		t.Fatalf("filter = %+v", store.filter)
+	}
+	if out.DatasetVersion != "2026-10-01" || len(out.MachineTypes) != 3 || out.MachineTypes[0].BilledVCPUs != 0.25 || out.MachineTypes[2].MemoryGB != 16 {
+		t.Fatalf("output = %+v", out)
+	}
+}
@@ -0,0 +1,30 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ToolMetrics receives the tool, error code (empty on success) and latency
+// of every tool call. Error codes are those stored in the audit log.
+type ToolMetrics interface {
+	ObserveToolCall(tool, code string, d time.Duration)
+}
+
+// Metrics returns middleware reporting every tools/call request to m.
+func Metrics(m ToolMetrics) sdk.Middleware {
+	return func(next sdk.MethodHandler) sdk.MethodHandler {
+		return func(ctx context.Context, method string, req sdk.Request) (sdk.Result, error) {
+			if method != "tools/call" {
+				return next(ctx, method, req)
+			}
+			start := time.Now()
+			res, err := next(ctx, method, req)
+			code, _ := errorCode(res, err)
+			m.ObserveToolCall(req.GetParams().(*sdk.CallToolParamsRaw).Name, code, time.Since(start))
+			return res, err
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type recordedCall struct{ tool, code string }
+
+type fakeToolMetrics struct{ calls []recordedCall }
+
+func (f *fakeToolMetrics) ObserveToolCall(tool, code string, d time.Duration) {
+	f.calls = append(f.calls, recordedCall{tool, code})
+}
+
+func TestMetrics(t *testing.T) {
+	m := &fakeToolMetrics{}
+	h := Metrics(m)(func(ctx context.Context, method string, req sdk.Request) (sdk.Result, error) {
+		if req.GetParams().(*sdk.CallToolParamsRaw).Name == "broken" {
+			return nil, errors.New("boom")
+		}
+		return &sdk.CallToolResult{}, nil
+	})
+	ctx := context.Background()
+	h(ctx, "tools/call", callRequest(t, "search", `{}`))
+	h(ctx, "tools/call", callRequest(t, "broken", `{}`))
+	want := []recordedCall{{"search", ""}, {"broken", "internal"}}
+	if len(m.calls) != len(want) || m.calls[0] != want[0] || m.calls[1] != want[1] {
+		t.Fatalf("calls = %v, want %v", m.calls, want)
+	}
+}
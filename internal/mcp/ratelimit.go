@@ -0,0 +1,44 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/ratelimit"
+)
+
+// codeRateLimited is the JSON-RPC error code returned when a tool call exceeds
+// a rate limit or the daily quota.
+const codeRateLimited = -32029
+
+// RateLimit returns middleware that applies the per-tool rate limits and the
+// daily quota of e to tools/call requests.
+func RateLimit(e *ratelimit.Enforcer) sdk.Middleware {
+	return func(next sdk.MethodHandler) sdk.MethodHandler {
+		return func(ctx context.Context, method string, req sdk.Request) (sdk.Result, error) {
+			if method != "tools/call" {
+				return next(ctx, method, req)
+			}
+			p, _ := principalFromRequest(req)
+			name := req.GetParams().(*sdk.CallToolParamsRaw).Name
+			err := e.CheckTool(ctx, p, name)
+			var exceeded *ratelimit.ExceededError
+			if errors.As(err, &exceeded) {
+				data, _ := json.Marshal(map[string]any{
+					"tool":                name,
+					"limit":               exceeded.Limit,
+					"retry_after_seconds": exceeded.RetryAfterSeconds(),
+				})
+				return nil, &jsonrpc.Error{Code: codeRateLimited, Message: exceeded.Error(), Data: data}
+			}
+			if err != nil {
+				return nil, err
+			}
+			return next(ctx, method, req)
+		}
+	}
+}
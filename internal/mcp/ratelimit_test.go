@@ -0,0 +1,40 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/auth"
+	"mcp-server/internal/ratelimit"
+)
+
+func TestRateLimit(t *testing.T) {
+	e := ratelimit.NewEnforcer(ratelimit.Policy{Tool: ratelimit.Rate{PerSecond: 1, Burst: 1}}, nil)
+	next := func(ctx context.Context, method string, req sdk.Request) (sdk.Result, error) {
+		return &sdk.CallToolResult{}, nil
+	}
+	h := RateLimit(e)(next)
+	info, err := VerifyPrincipal(auth.WithPrincipal(context.Background(), auth.Principal{Login: "alice"}), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &sdk.CallToolRequest{
+		Params: &sdk.CallToolParamsRaw{Name: "search"},
+		Extra:  &sdk.RequestExtra{TokenInfo: info},
+	}
+	if _, err := h(context.Background(), "tools/call", req); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	_, err = h(context.Background(), "tools/call", req)
+	var rpcErr *jsonrpc.Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != codeRateLimited {
+		t.Fatalf("second call: got %v, want rate limited error", err)
+	}
+	if _, err := h(context.Background(), "tools/list", req); err != nil {
+		t.Fatalf("tools/list must not be limited: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/database"
+)
+
+// CatalogStore searches SKUs and regions.
+type CatalogStore interface {
+	SearchSKUs(ctx context.Context, f database.SKUFilter) ([]database.SKU, error)
+	RegionByID(ctx context.Context, regionID string) (database.Region, error)
+	ListRegions(ctx context.Context, f database.RegionFilter) ([]database.Region, error)
+}
+
+var _ CatalogStore = (*database.SQLRepository)(nil)
+
+// validateRegion rejects region IDs missing from the regions table. An empty
+// region is valid and means any region.
+func validateRegion(ctx context.Context, store CatalogStore, region string) error {
+	if region == "" {
+		return nil
+	}
+	_, err := store.RegionByID(ctx, region)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("unknown region %q; call list_regions for valid region IDs such as us-central1", region)
+	}
+	return err
+}
+
+// ListRegionsInput filters regions.
+type ListRegionsInput struct {
+	Continent   string `json:"continent,omitempty" jsonschema:"continent such as Europe or North America"`
+	Country     string `json:"country,omitempty" jsonschema:"country such as Germany"`
+	MultiRegion string `json:"multi_region,omitempty" jsonschema:"only regions belonging to this multi-region: us, europe or asia"`
+}
+
+// RegionInfo describes a region.
+type RegionInfo struct {
+	ID           string   `json:"id"`
+	DisplayName  string   `json:"display_name"`
+	Continent    string   `json:"continent"`
+	Country      string   `json:"country,omitempty"`
+	MultiRegion  bool     `json:"multi_region,omitempty"`
+	MultiRegions []string `json:"multi_regions,omitempty"`
+	Zones        []string `json:"zones,omitempty"`
+}
+
+// ListRegionsOutput lists regions ordered by ID.
+type ListRegionsOutput struct {
+	Regions []RegionInfo `json:"regions"`
+}
+
+func listRegionsTool(store CatalogStore) (*sdk.Tool, sdk.ToolHandlerFor[ListRegionsInput, ListRegionsOutput]) {
+	tool := &sdk.Tool{
+		Name:        "list_regions",
+		Description: "Lists Google Cloud regions and multi-regions with location, multi-region membership and zones.",
+	}
+	handler := func(ctx context.Context, req *sdk.CallToolRequest, in ListRegionsInput) (*sdk.CallToolResult, ListRegionsOutput, error) {
+		regions, err := store.ListRegions(ctx, database.RegionFilter{Continent: in.Continent, Country: in.Country, MultiRegion: in.MultiRegion})
+		if err != nil {
+			return nil, ListRegionsOutput{}, err
+		}
+		out := ListRegionsOutput{Regions: make([]RegionInfo, 0, len(regions))}
+		for _, g := range regions {
+			out.Regions = append(out.Regions, RegionInfo{
+				ID:           g.RegionID,
+				DisplayName:  g.DisplayName,
+				Continent:    g.Continent,
+				Country:      g.Country,
+				MultiRegion:  g.MultiRegion,
+				MultiRegions: g.MultiRegions,
+				Zones:        g.Zones,
+			})
+		}
+		return nil, out, nil
+	}
+	return tool, handler
+}
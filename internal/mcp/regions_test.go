@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"mcp-server/internal/database"
+)
+
+type fakeCatalog struct {
+	regions []database.Region
+	skus    []database.SKU
+	filter  database.SKUFilter
+}
+
+func (f *fakeCatalog) SearchSKUs(ctx context.Context, filter database.SKUFilter) ([]database.SKU, error) {
+	f.filter = filter
+	return f.skus, nil
+}
+
+func (f *fakeCatalog) RegionByID(ctx context.Context, regionID string) (database.Region, error) {
+	for _, g := range f.regions {
+		if g.RegionID == regionID {
+			return g, nil
+		}
+	}
+	return database.Region{}, sql.ErrNoRows
+}
+
+func (f *fakeCatalog) ListRegions(ctx context.Context, filter database.RegionFilter) ([]database.Region, error) {
+	var out []database.Region
+	for _, g := range f.regions {
+		if filter.Continent == "" || strings.EqualFold(g.Continent, filter.Continent) {
+			out = append(out, g)
+		}
+	}
+	return out, nil
+}
+
+var testRegions = []database.Region{
+	{RegionID: "europe-west1", DisplayName: "St. Ghislain", Continent: "Europe", Country: "Belgium"},
+	{RegionID: "us-central1", DisplayName: "Council Bluffs, Iowa", Continent: "North America", Country: "United States", MultiRegions: []string{"us"}},
+	{RegionID: "us-east1", DisplayName: "Moncks Corner, South Carolina", Continent: "North America", Country: "United States", MultiRegions: []string{"us"}},
+}
+
+func TestListRegionsTool(t *testing.T) {
+	_, handler := listRegionsTool(&fakeCatalog{regions: testRegions})
+	_, out, err := handler(context.Background(), nil, ListRegionsInput{Continent: "north america"})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(out.Regions) != 2 || out.Regions[0].ID != "us-central1" || out.Regions[0].MultiRegions[0] != "us" {
+		t.Fatalf("output = %+v", out)
+	}
+}
+
+func TestValidateRegion(t *testing.T) {
+	store := &fakeCatalog{regions: testRegions}
+	for _, region := range []string{"", "us-central1"} {
+		if err := validateRegion(context.Background(), store, region); err != nil {
+			t.Fatalf("validateRegion(%q): %v", region, err)
+		}
+	}
+	err := validateRegion(context.Background(), store, "us-central")
+	if err == nil || !strings.Contains(err.Error(), "list_regions") {
+		t.Fatalf("unknown region: got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/database"
+)
+
+// SearchInput filters SKUs.
+type SearchInput struct {
+	Query          string `json:"query,omitempty" jsonschema:"words that must all appear in the SKU description, such as n2 core"`
+	ServiceID      string `json:"service_id,omitempty" jsonschema:"service ID such as 6F81-5844-456A for Compute Engine"`
+	ResourceFamily string `json:"resource_family,omitempty" jsonschema:"resource family such as Compute, Storage or Network"`
+	Region         string `json:"region,omitempty" jsonschema:"only SKUs offered in this region, such as us-central1"`
+	Limit          int    `json:"limit,omitempty" jsonschema:"maximum number of SKUs to return, default 50"`
+}
+
+// SKUSummary identifies a SKU in search results.
+type SKUSummary struct {
+	SKUID          string   `json:"sku_id"`
+	ServiceID      string   `json:"service_id"`
+	Description    string   `json:"description"`
+	ResourceFamily string   `json:"resource_family,omitempty"`
+	ResourceGroup  string   `json:"resource_group,omitempty"`
+	UsageType      string   `json:"usage_type,omitempty"`
+	Regions        []string `json:"regions,omitempty"`
+}
+
+// SearchOutput lists matching SKUs ordered by description.
+type SearchOutput struct {
+	SKUs []SKUSummary `json:"skus"`
+}
+
+func searchTool(store CatalogStore) (*sdk.Tool, sdk.ToolHandlerFor[SearchInput, SearchOutput]) {
+	tool := &sdk.Tool{
+		Name:        "search",
+		Description: "Searches Google Cloud SKUs by description words, service, resource family and region. Use details for the pricing of a result.",
+	}
+	handler := func(ctx context.Context, req *sdk.CallToolRequest, in SearchInput) (*sdk.CallToolResult, SearchOutput, error) {
+		if in.Query == "" && in.ServiceID == "" && in.ResourceFamily == "" {
+			return nil, SearchOutput{}, errors.New("set at least one of query, service_id or resource_family")
+		}
+		if err := validateRegion(ctx, store, in.Region); err != nil {
+			return nil, SearchOutput{}, err
+		}
+		skus, err := store.SearchSKUs(ctx, database.SKUFilter{
+			Query:          in.Query,
+			ServiceID:      in.ServiceID,
+			ResourceFamily: in.ResourceFamily,
+			Region:         in.Region,
+			Limit:          min(in.Limit, 200),
+		})
+		if err != nil {
+			return nil, SearchOutput{}, err
+		}
+		out := SearchOutput{SKUs: make([]SKUSummary, 0, len(skus))}
+		for _, s := range skus {
+			out.SKUs = append(out.SKUs, SKUSummary{
+				SKUID:          s.SKUID,
+				ServiceID:      s.ServiceID,
+				Description:    s.Description,
+				ResourceFamily: s.Category.ResourceFamily,
+				ResourceGroup:  s.Category.ResourceGroup,
+				UsageType:      s.Category.UsageType,
+				Regions:        s.ServiceRegions,
+			})
+		}
+		return nil, out, nil
+	}
+	return tool, handler
+}
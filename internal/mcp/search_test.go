@@ -0,0 +1,34 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"mcp-server/internal/database"
+)
+
+func TestSearchTool(t *testing.T) {
+	store := &fakeCatalog{
+		regions: testRegions,
+		skus:    []database.SKU{{SKUID: "cpu", ServiceID: "compute", Description: "N2 Instance Core", Category: database.Category{ResourceFamily: "Compute"}}},
+	}
+	_, handler := searchTool(store)
+	ctx := context.Background()
+
+	_, out, err := handler(ctx, nil, SearchInput{Query: "n2 core", Region: "us-central1", Limit: 1000})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(out.SKUs) != 1 || out.SKUs[0].ResourceFamily != "Compute" {
+		t.Fatalf("output = %+v", out)
+	}
+	if store.filter.Region != "us-central1" || store.filter.Limit != 200 {
+		t.Fatalf("filter = %+v", store.filter)
+	}
+	if _, _, err := handler(ctx, nil, SearchInput{Query: "core", Region: "mars-north1"}); err == nil {
+		t.Fatal("expected error for unknown region")
+	}
+	if _, _, err := handler(ctx, nil, SearchInput{Region: "us-central1"}); err == nil {
+		t.Fatal("expected error for a search without criteria")
+	}
+}
@@ -1 +1,120 @@
+// Package mcp exposes Google Cloud pricing data to AI agents as MCP tools.
 package mcp
+
+import (
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/auth"
+	"mcp-server/internal/ratelimit"
+)
+
+// Name and Version identify the server to MCP clients.
+const (
+	Name    = "cloud-pricing-mcp"
+	Version = "0.1.0"
+)
+
+// toolRoles lists the tools that require more than auth.RoleUser, such as
+// operational tools reserved for auth.RoleAdmin.
+var toolRoles = ToolRoles{
+	"query_tool_calls": auth.RoleAdmin,
+}
+
+// Options configures the MCP server.
+type Options struct {
+	// SKUs backs the details, calculate, compare_regions, estimate_vm,
+	// compare_commitments and estimate tools, usually through a read cache.
+	SKUs SKUStore
+	// Catalog backs the search, list_regions, compare_regions, estimate_vm,
+	// compare_commitments and estimate tools and region validation.
+	Catalog CatalogStore
+	// MachineTypes backs the list_machine_types tool and machine types in
+	// estimate_vm.
+	MachineTypes MachineTypeStore
+	// Estimates backs the saved estimate tools, which also need SKUs and
+	// Catalog to price them.
+	Estimates EstimateStore
+	// ToolCalls backs the query_tool_calls tool.
+	ToolCalls ToolCallStore
+	// Audit records tool calls; nil disables auditing.
+	Audit *AuditLog
+	// Limits applies rate limits and quotas; nil disables them.
+	Limits *ratelimit.Enforcer
+	// Metrics counts tool calls, errors and latency; nil disables them.
+	Metrics ToolMetrics
+	// InFlight tracks running tool calls for graceful shutdown; nil disables it.
+	InFlight *InFlight
+}
+
+// NewServer creates the MCP server with all tools registered, tool calls
+// traced and per-tool role checks installed. Audited and counted calls
+// include those rejected by role checks or rate limits.
+func NewServer(opts Options) *sdk.Server {
+	s := sdk.NewServer(&sdk.Implementation{Name: Name, Version: Version}, nil)
+	// Middleware listed first runs first. Log context and tracing cover
+	// everything logged while handling a call; metrics and the audit log see
+	// the calls the role check or rate limiter reject, and the role check
+	// rejects calls before they use up rate limits or quota.
+	middleware := []sdk.Middleware{LogContext, Tracing}
+	if opts.InFlight != nil {
+		middleware = append(middleware, opts.InFlight.Middleware)
+	}
+	if opts.Audit != nil {
+		middleware = append(middleware, opts.Audit.Middleware)
+	}
+	if opts.Metrics != nil {
+		middleware = append(middleware, Metrics(opts.Metrics))
+	}
+	middleware = append(middleware, toolRoles.Middleware)
+	if opts.Limits != nil {
+		middleware = append(middleware, RateLimit(opts.Limits))
+	}
+	s.AddReceivingMiddleware(middleware...)
+	if opts.SKUs != nil {
+		tool, handler := detailsTool(opts.SKUs)
+		sdk.AddTool(s, tool, handler)
+	}
+	if opts.Catalog != nil {
+		tool, handler := searchTool(opts.Catalog)
+		sdk.AddTool(s, tool, handler)
+		regionsTool, regionsHandler := listRegionsTool(opts.Catalog)
+		sdk.AddTool(s, regionsTool, regionsHandler)
+	}
+	if opts.SKUs != nil && opts.Catalog != nil {
+		tool, handler := calculateTool(opts.SKUs, opts.Catalog)
+		sdk.AddTool(s, tool, handler)
+		compareTool, compareHandler := compareRegionsTool(opts.SKUs, opts.Catalog)
+		sdk.AddTool(s, compareTool, compareHandler)
+		vmTool, vmHandler := estimateVMTool(opts.SKUs, opts.Catalog, opts.MachineTypes)
+		sdk.AddTool(s, vmTool, vmHandler)
+		commitTool, commitHandler := compareCommitmentsTool(opts.SKUs, opts.Catalog)
+		sdk.AddTool(s, commitTool, commitHandler)
+		bomTool, bomHandler := estimateTool(opts.SKUs, opts.Catalog)
+		sdk.AddTool(s, bomTool, bomHandler)
+	}
+	if opts.Estimates != nil && opts.SKUs != nil && opts.Catalog != nil {
+		saveTool, saveHandler := saveEstimateTool(opts.SKUs, opts.Catalog, opts.Estimates)
+		sdk.AddTool(s, saveTool, saveHandler)
+		listTool, listHandler := listEstimatesTool(opts.Estimates)
+		sdk.AddTool(s, listTool, listHandler)
+		loadTool, loadHandler := loadEstimateTool(opts.Estimates)
+		sdk.AddTool(s, loadTool, loadHandler)
+		repriceTool, repriceHandler := repriceEstimateTool(opts.SKUs, opts.Catalog, opts.Estimates)
+		sdk.AddTool(s, repriceTool, repriceHandler)
+		shareTool, shareHandler := shareEstimateTool(opts.Estimates)
+		sdk.AddTool(s, shareTool, shareHandler)
+		deleteTool, deleteHandler := deleteEstimateTool(opts.Estimates)
+		sdk.AddTool(s, deleteTool, deleteHandler)
+		driftTool, driftHandler := estimateDriftTool(opts.Estimates)
+		sdk.AddTool(s, driftTool, driftHandler)
+	}
+	if opts.MachineTypes != nil {
+		tool, handler := listMachineTypesTool(opts.MachineTypes)
+		sdk.AddTool(s, tool, handler)
+	}
+	if opts.ToolCalls != nil {
+		tool, handler := queryToolCallsTool(opts.ToolCalls)
+		sdk.AddTool(s, tool, handler)
+	}
+	return s
+}
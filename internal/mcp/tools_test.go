@@ -0,0 +1,150 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/auth"
+	"mcp-server/internal/logging"
+	"mcp-server/internal/ratelimit"
+)
+
+func TestNewServer(t *testing.T) {
+	// AddTool panics on tool schemas it cannot infer, so building the server
+	// checks every registered tool.
+	if s := NewServer(Options{
+		SKUs:         fakeSKUs{},
+		Catalog:      &fakeCatalog{},
+		MachineTypes: &fakeMachineTypes{},
+		Estimates:    newFakeEstimates(),
+		ToolCalls:    &fakeToolCalls{},
+	}); s == nil {
+		t.Fatal("NewServer returned nil")
+	}
+}
+
+// connect serves s over in-memory transports to a client whose tool calls are
+// made as p in HTTP requests with ID req-test.
+func connect(t *testing.T, s *sdk.Server, p auth.Principal) *sdk.ClientSession {
+	t.Helper()
+	s.AddReceivingMiddleware(func(next sdk.MethodHandler) sdk.MethodHandler {
+		return func(ctx context.Context, method string, req sdk.Request) (sdk.Result, error) {
+			if r, ok := req.(*sdk.CallToolRequest); ok {
+				info, err := VerifyPrincipal(auth.WithPrincipal(ctx, p), "", nil)
+				if err != nil {
+					return nil, err
+				}
+				if r.Extra == nil {
+					r.Extra = &sdk.RequestExtra{}
+				}
+				r.Extra.TokenInfo = info
+				r.Extra.Header = http.Header{logging.RequestIDHeader: {"req-test"}}
+			}
+			return next(ctx, method, req)
+		}
+	})
+	ctx := context.Background()
+	serverTransport, clientTransport := sdk.NewInMemoryTransports()
+	ss, err := s.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server connect: %v", err)
+	}
+	t.Cleanup(func() { ss.Close() })
+	cs, err := sdk.NewClient(&sdk.Implementation{Name: "test-client"}, nil).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect: %v", err)
+	}
+	t.Cleanup(func() { cs.Close() })
+	return cs
+}
+
+func TestNewServer_RoleCheckBeforeRateLimit(t *testing.T) {
+	limits := ratelimit.NewEnforcer(ratelimit.Policy{Tool: ratelimit.Rate{PerSecond: 0.001, Burst: 1}}, nil)
+	s := NewServer(Options{ToolCalls: &fakeToolCalls{}, Limits: limits})
+	cs := connect(t, s, auth.Principal{Login: "alice", Role: auth.RoleUser})
+	ctx := context.Background()
+
+	params := &sdk.CallToolParams{Name: "query_tool_calls", Arguments: map[string]any{}}
+	for range 2 {
+		if _, err := cs.CallTool(ctx, params); err == nil || !strings.Contains(err.Error(), "requires the admin role") {
+			t.Fatalf("non-admin call: got %v, want access denied", err)
+		}
+	}
+}
+
+func TestNewServer_RecordsRejectedCalls(t *testing.T) {
+	store := &fakeToolCalls{}
+	audit := NewAuditLog(store, nil, 10)
+	limits := ratelimit.NewEnforcer(ratelimit.Policy{Tool: ratelimit.Rate{PerSecond: 0.001, Burst: 1}}, nil)
+	metrics := &fakeToolMetrics{}
+	s := NewServer(Options{Catalog: &fakeCatalog{regions: testRegions}, ToolCalls: store, Audit: audit, Metrics: metrics, Limits: limits})
+	cs := connect(t, s, auth.Principal{Login: "alice", Role: auth.RoleUser})
+	ctx := context.Background()
+
+	if _, err := cs.CallTool(ctx, &sdk.CallToolParams{Name: "query_tool_calls", Arguments: map[string]any{}}); err == nil {
+		t.Fatal("non-admin call to query_tool_calls succeeded")
+	}
+	regions := &sdk.CallToolParams{Name: "list_regions", Arguments: map[string]any{}}
+	if _, err := cs.CallTool(ctx, regions); err != nil {
+		t.Fatalf("list_regions: %v", err)
+	}
+	if _, err := cs.CallTool(ctx, regions); err == nil {
+		t.Fatal("rate limit not applied")
+	}
+	if err := audit.Close(ctx); err != nil {
+		t.Fatalf("close audit log: %v", err)
+	}
+
+	want := []struct{ tool, code string }{
+		{"query_tool_calls", "-32003"},
+		{"list_regions", ""},
+		{"list_regions", "-32029"},
+	}
+	if len(store.calls) != len(want) {
+		t.Fatalf("audited %d calls, want %d: %+v", len(store.calls), len(want), store.calls)
+	}
+	for i, c := range store.calls {
+		if c.Tool != want[i].tool || c.ErrorCode != want[i].code || c.Principal != "github:alice" {
+			t.Errorf("audit record %d = %+v, want tool %s and code %q", i, c, want[i].tool, want[i].code)
+		}
+	}
+	if len(metrics.calls) != len(want) {
+		t.Fatalf("counted %d calls, want %d: %+v", len(metrics.calls), len(want), metrics.calls)
+	}
+	for i, c := range metrics.calls {
+		if c.tool != want[i].tool || c.code != want[i].code {
+			t.Errorf("counted call %d = %+v, want tool %s and code %q", i, c, want[i].tool, want[i].code)
+		}
+	}
+}
+
+func TestNewServer_LogContextOutermost(t *testing.T) {
+	var buf bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(logging.New(&buf, "warn"))
+
+	// A closed audit log warns about every record it drops.
+	audit := NewAuditLog(&fakeToolCalls{}, nil, 1)
+	if err := audit.Close(context.Background()); err != nil {
+		t.Fatalf("close audit log: %v", err)
+	}
+	s := NewServer(Options{Catalog: &fakeCatalog{regions: testRegions}, Audit: audit})
+	cs := connect(t, s, auth.Principal{Login: "alice", Role: auth.RoleUser})
+	if _, err := cs.CallTool(context.Background(), &sdk.CallToolParams{Name: "list_regions", Arguments: map[string]any{}}); err != nil {
+		t.Fatalf("list_regions: %v", err)
+	}
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("decode %q: %v", buf.String(), err)
+	}
+	if line[logging.KeyRequestID] != "req-test" {
+		t.Fatalf("audit warning = %v, want request ID req-test", line)
+	}
+}
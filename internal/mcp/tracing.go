@@ -0,0 +1,47 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"mcp-server/internal/tracing"
+)
+
+var tracer = otel.Tracer("mcp-server/internal/mcp")
+
+// Tracing wraps every tools/call request in a span. Tool handlers of a
+// streamable session run in the session's context rather than the HTTP
+// request's, so the parent span is taken from the trace context headers of
+// the request that carried the call.
+func Tracing(next sdk.MethodHandler) sdk.MethodHandler {
+	return func(ctx context.Context, method string, req sdk.Request) (sdk.Result, error) {
+		if method != "tools/call" {
+			return next(ctx, method, req)
+		}
+		if extra := req.GetExtra(); extra != nil && extra.Header != nil {
+			ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(http.Header(extra.Header)))
+		}
+		tool := req.GetParams().(*sdk.CallToolParamsRaw).Name
+		ctx, span := tracer.Start(ctx, "tools/call "+tool,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attribute.String("mcp.method.name", method), attribute.String("mcp.tool.name", tool)))
+		if ss, ok := req.GetSession().(*sdk.ServerSession); ok && ss != nil && ss.ID() != "" {
+			span.SetAttributes(attribute.String("mcp.session.id", ss.ID()))
+		}
+		res, err := next(ctx, method, req)
+		if code, msg := errorCode(res, err); code != "" {
+			span.SetAttributes(attribute.String("mcp.error.code", code))
+			if err == nil {
+				span.SetAttributes(attribute.String("mcp.error.message", msg))
+			}
+		}
+		tracing.End(span, err)
+		return res, err
+	}
+}
@@ -0,0 +1,180 @@
+// Package metrics defines the Prometheus metrics of the MCP server and the
+// sync job and the ways they are exposed: scraped over HTTP by the server,
+// pushed or written to a textfile by the short-lived job.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+const namespace = "cloud_pricing"
+
+// NewRegistry returns a registry with the Go runtime and process collectors.
+func NewRegistry() *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	return reg
+}
+
+// Handler serves the metrics gathered by g in the Prometheus exposition format.
+func Handler(g prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(g, promhttp.HandlerOpts{})
+}
+
+// Server holds the metrics of the MCP server.
+type Server struct {
+	toolCalls   *prometheus.CounterVec
+	toolErrors  *prometheus.CounterVec
+	toolLatency *prometheus.HistogramVec
+	dbLatency   *prometheus.HistogramVec
+	authResults *prometheus.CounterVec
+	cacheLookup *prometheus.CounterVec
+}
+
+// NewServer creates the MCP server metrics and registers them with reg.
+func NewServer(reg prometheus.Registerer) *Server {
+	m := &Server{
+		toolCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "tool_calls_total",
+			Help: "MCP tool calls by tool.",
+		}, []string{"tool"}),
+		toolErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "tool_errors_total",
+			Help: "Failed MCP tool calls by tool and error code.",
+		}, []string{"tool", "code"}),
+		toolLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Name: "tool_call_duration_seconds",
+			Help:    "MCP tool call latency by tool.",
+			Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		}, []string{"tool"}),
+		dbLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Name: "db_query_duration_seconds",
+			Help:    "Database query latency by statement kind and outcome.",
+			Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"op", "outcome"}),
+		authResults: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "auth_requests_total",
+			Help: "Authentication attempts by outcome.",
+		}, []string{"outcome"}),
+		cacheLookup: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "cache_lookups_total",
+			Help: "Read cache lookups by kind and result (hit or miss).",
+		}, []string{"kind", "result"}),
+	}
+	reg.MustRegister(m.toolCalls, m.toolErrors, m.toolLatency, m.dbLatency, m.authResults, m.cacheLookup)
+	return m
+}
+
+// ObserveToolCall records a tool call; code is empty for successful calls.
+func (m *Server) ObserveToolCall(tool, code string, d time.Duration) {
+	m.toolCalls.WithLabelValues(tool).Inc()
+	if code != "" {
+		m.toolErrors.WithLabelValues(tool, code).Inc()
+	}
+	m.toolLatency.WithLabelValues(tool).Observe(d.Seconds())
+}
+
+// ObserveQuery records a database statement.
+func (m *Server) ObserveQuery(op string, d time.Duration, err error) {
+	m.dbLatency.WithLabelValues(op, outcome(err)).Observe(d.Seconds())
+}
+
+// AuthOutcome counts an authentication attempt.
+func (m *Server) AuthOutcome(outcome string) {
+	m.authResults.WithLabelValues(outcome).Inc()
+}
+
+// CacheLookup counts a read cache lookup.
+func (m *Server) CacheLookup(kind string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cacheLookup.WithLabelValues(kind, result).Inc()
+}
+
+// Sync holds the metrics of one sync job run.
+type Sync struct {
+	services    prometheus.Counter
+	skus        prometheus.Counter
+	apiErrors   *prometheus.CounterVec
+	duration    prometheus.Gauge
+	lastSuccess prometheus.Gauge
+	lastRun     *prometheus.GaugeVec
+}
+
+// NewSync creates the sync job metrics and registers them with reg.
+func NewSync(reg prometheus.Registerer) *Sync {
+	m := &Sync{
+		services: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "sync", Name: "services_processed_total",
+			Help: "Services written by the last sync run.",
+		}),
+		skus: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "sync", Name: "skus_processed_total",
+			Help: "SKUs written by the last sync run.",
+		}),
+		apiErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "sync", Name: "api_errors_total",
+			Help: "Cloud Billing Catalog API errors by gRPC status code.",
+		}, []string{"code"}),
+		duration: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace, Subsystem: "sync", Name: "run_duration_seconds",
+			Help: "Duration of the last sync run.",
+		}),
+		lastSuccess: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace, Subsystem: "sync", Name: "last_success_timestamp_seconds",
+			Help: "Unix time the last successful sync run finished.",
+		}),
+		lastRun: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace, Subsystem: "sync", Name: "last_run_timestamp_seconds",
+			Help: "Unix time the last sync run finished, by outcome.",
+		}, []string{"outcome"}),
+	}
+	reg.MustRegister(m.services, m.skus, m.apiErrors, m.duration, m.lastSuccess, m.lastRun)
+	return m
+}
+
+// ServicesProcessed counts services written to the database.
+func (m *Sync) ServicesProcessed(n int) { m.services.Add(float64(n)) }
+
+// SKUsProcessed counts SKUs written to the database.
+func (m *Sync) SKUsProcessed(n int) { m.skus.Add(float64(n)) }
+
+// APIError counts a failed catalog API call.
+func (m *Sync) APIError(code string) { m.apiErrors.WithLabelValues(code).Inc() }
+
+// RunFinished records the duration and outcome of a run.
+func (m *Sync) RunFinished(d time.Duration, err error) {
+	m.duration.Set(d.Seconds())
+	now := float64(time.Now().Unix())
+	m.lastRun.WithLabelValues(outcome(err)).Set(now)
+	if err == nil {
+		m.lastSuccess.Set(now)
+	}
+}
+
+// Push replaces the metrics of job on the Pushgateway at url with those gathered by g.
+func Push(ctx context.Context, url, job string, g prometheus.Gatherer) error {
+	return push.New(url, job).Gatherer(g).PushContext(ctx)
+}
+
+// WriteTextfile atomically writes the metrics gathered by g to path for the
+// node exporter textfile collector.
+func WriteTextfile(path string, g prometheus.Gatherer) error {
+	return prometheus.WriteToTextfile(path, g)
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestServer(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewServer(reg)
+	m.ObserveToolCall("search", "", 10*time.Millisecond)
+	m.ObserveToolCall("search", "-32029", time.Millisecond)
+	m.ObserveQuery("select", time.Millisecond, errors.New("boom"))
+	m.AuthOutcome("denied")
+	m.CacheLookup("sku", true)
+	m.CacheLookup("sku", false)
+
+	if got := testutil.ToFloat64(m.toolCalls.WithLabelValues("search")); got != 2 {
+		t.Fatalf("tool calls = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.toolErrors.WithLabelValues("search", "-32029")); got != 1 {
+		t.Fatalf("tool errors = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.authResults.WithLabelValues("denied")); got != 1 {
+		t.Fatalf("auth outcomes = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.cacheLookup.WithLabelValues("sku", "hit")); got != 1 {
+		t.Fatalf("cache hits = %v, want 1", got)
+	}
+	if n := testutil.CollectAndCount(m.dbLatency); n != 1 {
+		t.Fatalf("db latency series = %d, want 1", n)
+	}
+}
+
+func TestSync_WriteTextfile(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewSync(reg)
+	m.ServicesProcessed(3)
+	m.SKUsProcessed(40)
+	m.APIError("Unavailable")
+	m.RunFinished(2*time.Second, nil)
+
+	path := filepath.Join(t.TempDir(), "sync.prom")
+	if err := WriteTextfile(path, reg); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"cloud_pricing_sync_services_processed_total 3",
+		"cloud_pricing_sync_skus_processed_total 40",
+		`cloud_pricing_sync_api_errors_total{code="Unavailable"} 1`,
+		"cloud_pricing_sync_run_duration_seconds 2",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("textfile missing %q:\n%s", want, data)
+		}
+	}
+}
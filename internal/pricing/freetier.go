@@ -0,0 +1,69 @@
+package pricing
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"mcp-server/internal/database"
+)
+
+// freetier.json lists free allowances the catalog does not encode as free
+// tiers, such as monthly quotas granted per billing account.
+//
+//go:embed freetier.json
+var freeTierJSON []byte
+
+// Allowance is a free monthly quantity of a SKU, or of the SKUs of a service
+// whose description contains Description.
+type Allowance struct {
+	SKUID       string   `json:"sku_id"`
+	ServiceID   string   `json:"service_id"`
+	Description string   `json:"description"`
+	Regions     []string `json:"regions"` // empty when the allowance applies everywhere
+	Quantity    float64  `json:"quantity"`
+	UsageUnit   string   `json:"usage_unit"`
+	Note        string   `json:"note"`
+}
+
+var allowances []Allowance
+
+func init() {
+	if err := json.Unmarshal(freeTierJSON, &allowances); err != nil {
+		panic(fmt.Sprintf("pricing: invalid freetier.json: %v", err))
+	}
+}
+
+// FreeAllowance returns the free allowance of sku priced in usageUnit when
+// used in region. An allowance limited to some regions applies when region,
+// or the only region the SKU is offered in, is one of them.
+func FreeAllowance(sku database.SKU, usageUnit, region string) (Allowance, bool) {
+	if region == "" && len(sku.ServiceRegions) == 1 {
+		region = sku.ServiceRegions[0]
+	}
+	for _, a := range allowances {
+		switch {
+		case a.UsageUnit != usageUnit:
+		case a.SKUID != "" && a.SKUID != sku.SKUID:
+		case a.SKUID == "" && (a.ServiceID != sku.ServiceID || !strings.Contains(strings.ToLower(sku.Description), strings.ToLower(a.Description))):
+		case len(a.Regions) > 0 && !slices.Contains(a.Regions, region):
+		default:
+			return a, true
+		}
+	}
+	return Allowance{}, false
+}
+
+// FreeTierValue returns how much of quantity the allowance covers and what
+// that part would cost. The allowance covers the first units used, so it is
+// worth nothing where the catalog already prices them at zero.
+func FreeTierValue(p database.PricingInfo, a Allowance, quantity float64) (covered, amount float64, err error) {
+	covered = min(quantity, a.Quantity)
+	c, err := Calculate(p, covered)
+	if err != nil {
+		return 0, 0, err
+	}
+	return covered, c.Total, nil
+}
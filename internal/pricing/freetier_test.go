@@ -0,0 +1,42 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+
+	"mcp-server/internal/database"
+)
+
+func TestFreeAllowance(t *testing.T) {
+	storage := database.SKU{SKUID: "s", ServiceID: "95FF-2EF5-5EA1", Description: "Standard Storage US Regional", ServiceRegions: []string{"us-central1", "us-east1"}}
+	if _, ok := FreeAllowance(storage, "GiBy.mo", ""); ok {
+		t.Fatal("regional allowance applied without a region")
+	}
+	a, ok := FreeAllowance(storage, "GiBy.mo", "us-central1")
+	if !ok || a.Quantity != 5 {
+		t.Fatalf("FreeAllowance = %+v, %v", a, ok)
+	}
+	if _, ok := FreeAllowance(storage, "GiBy.mo", "europe-west1"); ok {
+		t.Fatal("allowance applied outside its regions")
+	}
+	if _, ok := FreeAllowance(storage, "GiBy.h", "us-central1"); ok {
+		t.Fatal("allowance applied to another unit")
+	}
+	run := database.SKU{SKUID: "r", ServiceID: "152E-C115-5142", Description: "CPU Allocation Time (tier 1)"}
+	if a, ok := FreeAllowance(run, "s", ""); !ok || a.Quantity != 180000 {
+		t.Fatalf("Cloud Run allowance = %+v, %v", a, ok)
+	}
+}
+
+func TestFreeTierValue(t *testing.T) {
+	a := Allowance{Quantity: 5}
+	covered, amount, err := FreeTierValue(rates([2]float64{0, 0.02}), a, 12)
+	if err != nil || covered != 5 || math.Abs(amount-0.1) > 1e-9 {
+		t.Fatalf("FreeTierValue = %v, %v, %v", covered, amount, err)
+	}
+	// Units the catalog already prices at zero are worth nothing.
+	covered, amount, err = FreeTierValue(rates([2]float64{0, 0}, [2]float64{10, 0.02}), a, 3)
+	if err != nil || covered != 3 || amount != 0 {
+		t.Fatalf("FreeTierValue over a free tier = %v, %v, %v", covered, amount, err)
+	}
+}
@@ -0,0 +1,70 @@
+// Package pricing computes costs from the tiered rates of catalog pricing.
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"mcp-server/internal/database"
+)
+
+// TierCost is the part of a quantity billed at one tier's unit price.
+type TierCost struct {
+	StartUsageAmount float64
+	Quantity         float64
+	UnitPrice        float64
+	Cost             float64
+}
+
+// Cost is the price of a quantity of a SKU, itemized by tier.
+type Cost struct {
+	Currency  string
+	UsageUnit string
+	Quantity  float64
+	Tiers     []TierCost // tiers the quantity reaches, in order
+	Total     float64
+}
+
+// UnitPrice converts a catalog amount to a float.
+func UnitPrice(m database.Money) float64 {
+	return float64(m.Units) + float64(m.Nanos)/1e9
+}
+
+// Calculate prices quantity, in the SKU's usage unit, against the tiered
+// rates of p. Each tier applies from its start amount up to the next tier's.
+func Calculate(p database.PricingInfo, quantity float64) (Cost, error) {
+	if quantity < 0 || math.IsNaN(quantity) || math.IsInf(quantity, 0) {
+		return Cost{}, fmt.Errorf("quantity %v must be a non-negative number", quantity)
+	}
+	if len(p.TieredRates) == 0 {
+		return Cost{}, errors.New("SKU has no rates")
+	}
+	c := Cost{Currency: p.CurrencyCode, UsageUnit: p.UsageUnit, Quantity: quantity}
+	for i, r := range p.TieredRates {
+		if i > 0 && quantity <= r.StartUsageAmount {
+			break
+		}
+		end := quantity
+		if i+1 < len(p.TieredRates) {
+			end = min(end, p.TieredRates[i+1].StartUsageAmount)
+		}
+		q := max(end-r.StartUsageAmount, 0)
+		price := UnitPrice(r.UnitPrice)
+		c.Tiers = append(c.Tiers, TierCost{StartUsageAmount: r.StartUsageAmount, Quantity: q, UnitPrice: price, Cost: q * price})
+		c.Total += q * price
+	}
+	return c, nil
+}
+
+// BaseUnitPrice returns the unit price of the first priced tier, skipping a
+// free allowance, for comparing SKUs independently of usage. It returns 0
+// when every tier is free.
+func BaseUnitPrice(p database.PricingInfo) float64 {
+	for _, r := range p.TieredRates {
+		if price := UnitPrice(r.UnitPrice); price > 0 {
+			return price
+		}
+	}
+	return 0
+}
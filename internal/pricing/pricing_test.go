@@ -0,0 +1,69 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+
+	"mcp-server/internal/database"
+)
+
+func rates(tiers ...[2]float64) database.PricingInfo {
+	p := database.PricingInfo{CurrencyCode: "USD", UsageUnit: "GiBy.mo"}
+	for _, t := range tiers {
+		units, frac := math.Modf(t[1])
+		p.TieredRates = append(p.TieredRates, database.TieredRate{
+			StartUsageAmount: t[0],
+			UnitPrice:        database.Money{CurrencyCode: "USD", Units: int64(units), Nanos: int32(math.Round(frac * 1e9))},
+		})
+	}
+	return p
+}
+
+func TestCalculate(t *testing.T) {
+	// Free first GiB, then 0.10 up to 100, then 0.05.
+	p := rates([2]float64{0, 0}, [2]float64{1, 0.10}, [2]float64{100, 0.05})
+	tests := []struct {
+		quantity float64
+		total    float64
+		tiers    int
+	}{
+		{0, 0, 1},
+		{0.5, 0, 1},
+		{10, 0.9, 2},
+		{100, 9.9, 2},
+		{300, 19.9, 3},
+	}
+	for _, tt := range tests {
+		c, err := Calculate(p, tt.quantity)
+		if err != nil {
+			t.Fatalf("Calculate(%v): %v", tt.quantity, err)
+		}
+		if math.Abs(c.Total-tt.total) > 1e-9 || len(c.Tiers) != tt.tiers {
+			t.Errorf("Calculate(%v) = total %v over %d tiers, want %v over %d", tt.quantity, c.Total, len(c.Tiers), tt.total, tt.tiers)
+		}
+	}
+}
+
+func TestCalculate_Errors(t *testing.T) {
+	if _, err := Calculate(rates([2]float64{0, 1}), -1); err == nil {
+		t.Error("negative quantity accepted")
+	}
+	if _, err := Calculate(database.PricingInfo{}, 1); err == nil {
+		t.Error("pricing without rates accepted")
+	}
+}
+
+func TestUnitPrice(t *testing.T) {
+	if got := UnitPrice(database.Money{Units: 2, Nanos: 250_000_000}); got != 2.25 {
+		t.Fatalf("UnitPrice = %v, want 2.25", got)
+	}
+}
+
+func TestBaseUnitPrice(t *testing.T) {
+	if got := BaseUnitPrice(rates([2]float64{0, 0}, [2]float64{1, 0.12}, [2]float64{1024, 0.08})); got != 0.12 {
+		t.Fatalf("BaseUnitPrice = %v, want 0.12", got)
+	}
+	if got := BaseUnitPrice(rates([2]float64{0, 0})); got != 0 {
+		t.Fatalf("BaseUnitPrice of a free SKU = %v, want 0", got)
+	}
+}
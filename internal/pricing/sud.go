@@ -0,0 +1,31 @@
+package pricing
+
+// SUDTier is the share of the list price charged for the part of a month a
+// resource runs beyond From, a fraction of the month between 0 and 1.
+type SUDTier struct {
+	From float64 `json:"from"`
+	Rate float64 `json:"rate"`
+}
+
+// SustainedUseMultiplier returns the share of the list price charged, on
+// average, for a resource that runs fraction of the month under the
+// sustained use discount tiers, which must be ordered by From. A fraction
+// above 1 is treated as the whole month.
+func SustainedUseMultiplier(tiers []SUDTier, fraction float64) float64 {
+	fraction = min(fraction, 1)
+	if fraction <= 0 || len(tiers) == 0 {
+		return 1
+	}
+	charged := 0.0
+	for i, t := range tiers {
+		end := 1.0
+		if i+1 < len(tiers) {
+			end = tiers[i+1].From
+		}
+		if fraction <= t.From {
+			break
+		}
+		charged += (min(fraction, end) - t.From) * t.Rate
+	}
+	return charged / fraction
+}
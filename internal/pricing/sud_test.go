@@ -0,0 +1,28 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSustainedUseMultiplier(t *testing.T) {
+	n1 := []SUDTier{{0, 1}, {0.25, 0.8}, {0.5, 0.6}, {0.75, 0.4}}
+	tests := []struct {
+		fraction float64
+		want     float64
+	}{
+		{0, 1},
+		{0.25, 1},
+		{0.5, 0.9},
+		{1, 0.7},
+		{2, 0.7},
+	}
+	for _, tt := range tests {
+		if got := SustainedUseMultiplier(n1, tt.fraction); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("SustainedUseMultiplier(%v) = %v, want %v", tt.fraction, got, tt.want)
+		}
+	}
+	if got := SustainedUseMultiplier(nil, 1); got != 1 {
+		t.Errorf("without tiers = %v, want 1", got)
+	}
+}
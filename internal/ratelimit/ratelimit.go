@@ -0,0 +1,194 @@
+// Package ratelimit throttles authenticated principals with token buckets and
+// daily quotas so a single runaway client cannot saturate the database.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"mcp-server/internal/auth"
+	"mcp-server/internal/database"
+)
+
+// Rate configures a token bucket. A zero Rate disables limiting.
+type Rate struct {
+	PerSecond float64 // sustained requests per second
+	Burst     int     // requests allowed at once
+}
+
+func (r Rate) enabled() bool {
+	return r.PerSecond > 0 && r.Burst > 0
+}
+
+// Policy configures the limits applied to each principal.
+type Policy struct {
+	// Principal limits all HTTP requests of a principal.
+	Principal Rate
+	// Tool limits calls of a principal to each individual tool.
+	Tool Rate
+	// Tools overrides Tool for specific tools.
+	Tools map[string]Rate
+	// DailyQuota caps the tool calls of a principal per UTC day; zero disables it.
+	DailyQuota int
+}
+
+// QuotaStore persists daily tool call counters.
+type QuotaStore interface {
+	IncrementToolCalls(ctx context.Context, principal string, at time.Time, limit int) (int, error)
+}
+
+var _ QuotaStore = (*database.SQLRepository)(nil)
+
+// ExceededError reports that a limit was hit and when the caller may retry.
+type ExceededError struct {
+	Limit      string // "principal", "tool" or "daily_quota"
+	RetryAfter time.Duration
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("%s rate limit exceeded; retry after %s", e.Limit, e.RetryAfter.Round(time.Second))
+}
+
+// RetryAfterSeconds returns the retry delay rounded up to whole seconds, as
+// used by the Retry-After header.
+func (e *ExceededError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// Enforcer applies a Policy.
+type Enforcer struct {
+	policy    Policy
+	quotas    QuotaStore
+	principal *limiter
+	now       func() time.Time
+
+	mu    sync.Mutex
+	tools map[string]*limiter
+}
+
+// NewEnforcer creates an Enforcer. A nil quotas store disables the daily quota.
+func NewEnforcer(policy Policy, quotas QuotaStore) *Enforcer {
+	e := &Enforcer{
+		policy: policy,
+		quotas: quotas,
+		now:    time.Now,
+		tools:  make(map[string]*limiter),
+	}
+	e.principal = newLimiter(policy.Principal)
+	return e
+}
+
+// Middleware rejects requests from principals over their request rate with
+// 429 Too Many Requests and a Retry-After header. It must run after
+// auth.Authenticator.Middleware.
+func (e *Enforcer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, _ := auth.PrincipalFromContext(r.Context())
+		if wait, ok := e.principal.allow(p.String(), e.now()); !ok {
+			err := &ExceededError{Limit: "principal", RetryAfter: wait}
+			w.Header().Set("Retry-After", strconv.Itoa(err.RetryAfterSeconds()))
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// CheckTool counts a call of tool by principal against the per-tool rate and
+// the daily quota. It returns an *ExceededError when the call must be rejected.
+func (e *Enforcer) CheckTool(ctx context.Context, principal auth.Principal, tool string) error {
+	now := e.now()
+	if wait, ok := e.toolLimiter(tool).allow(principal.String(), now); !ok {
+		return &ExceededError{Limit: "tool", RetryAfter: wait}
+	}
+	if e.quotas == nil || e.policy.DailyQuota <= 0 {
+		return nil
+	}
+	_, err := e.quotas.IncrementToolCalls(ctx, principal.String(), now, e.policy.DailyQuota)
+	if errors.Is(err, database.ErrQuotaExceeded) {
+		midnight := now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+		return &ExceededError{Limit: "daily_quota", RetryAfter: midnight.Sub(now)}
+	}
+	if err != nil {
+		// Fail open: an unavailable quota table must not take the tools down.
+		slog.WarnContext(ctx, "daily quota unavailable, allowing call", "principal", principal.String(), "error", err)
+	}
+	return nil
+}
+
+func (e *Enforcer) toolLimiter(tool string) *limiter {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	l, ok := e.tools[tool]
+	if !ok {
+		r, ok := e.policy.Tools[tool]
+		if !ok {
+			r = e.policy.Tool
+		}
+		l = newLimiter(r)
+		e.tools[tool] = l
+	}
+	return l
+}
+
+// limiter keeps one token bucket per key. Buckets idle long enough to have
+// refilled completely are indistinguishable from new ones and are dropped.
+type limiter struct {
+	rate    Rate
+	idle    time.Duration
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+type bucket struct {
+	lim  *rate.Limiter
+	seen time.Time
+}
+
+func newLimiter(r Rate) *limiter {
+	l := &limiter{rate: r, buckets: make(map[string]*bucket)}
+	if r.enabled() {
+		l.idle = max(time.Duration(float64(r.Burst)/r.PerSecond*float64(time.Second)), time.Minute)
+	}
+	return l
+}
+
+// allow takes a token for key. When none is available it reports how long
+// the caller must wait for one.
+func (l *limiter) allow(key string, now time.Time) (time.Duration, bool) {
+	if !l.rate.enabled() {
+		return 0, true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.swept) >= l.idle {
+		for k, b := range l.buckets {
+			if now.Sub(b.seen) >= l.idle {
+				delete(l.buckets, k)
+			}
+		}
+		l.swept = now
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{lim: rate.NewLimiter(rate.Limit(l.rate.PerSecond), l.rate.Burst)}
+		l.buckets[key] = b
+	}
+	b.seen = now
+	res := b.lim.ReserveN(now, 1)
+	if wait := res.DelayFrom(now); wait > 0 {
+		res.CancelAt(now)
+		return wait, false
+	}
+	return 0, true
+}
@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mcp-server/internal/auth"
+	"mcp-server/internal/database"
+)
+
+type fakeQuotas struct{ calls map[string]int }
+
+func (f *fakeQuotas) IncrementToolCalls(ctx context.Context, principal string, at time.Time, limit int) (int, error) {
+	if f.calls[principal] >= limit {
+		return 0, database.ErrQuotaExceeded
+	}
+	f.calls[principal]++
+	return f.calls[principal], nil
+}
+
+func TestEnforcer_Middleware(t *testing.T) {
+	e := NewEnforcer(Policy{Principal: Rate{PerSecond: 1, Burst: 2}}, nil)
+	now := time.Unix(0, 0)
+	e.now = func() time.Time { return now }
+	h := e.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	do := func(login string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{Login: login}))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	for i := 0; i < 2; i++ {
+		if rec := do("alice"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status %d", i, rec.Code)
+		}
+	}
+	rec := do("alice")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "1" {
+		t.Fatalf("over limit: status %d, Retry-After %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if rec := do("bob"); rec.Code != http.StatusOK {
+		t.Fatalf("other principal: status %d", rec.Code)
+	}
+	now = now.Add(time.Second)
+	if rec := do("alice"); rec.Code != http.StatusOK {
+		t.Fatalf("after refill: status %d", rec.Code)
+	}
+}
+
+func TestEnforcer_CheckTool(t *testing.T) {
+	e := NewEnforcer(Policy{
+		Tool:  Rate{PerSecond: 10, Burst: 10},
+		Tools: map[string]Rate{"calculate": {PerSecond: 0.5, Burst: 1}},
+	}, nil)
+	now := time.Unix(0, 0)
+	e.now = func() time.Time { return now }
+	ctx := context.Background()
+	p := auth.Principal{Login: "alice"}
+
+	if err := e.CheckTool(ctx, p, "calculate"); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	var exceeded *ExceededError
+	if err := e.CheckTool(ctx, p, "calculate"); !errors.As(err, &exceeded) || exceeded.Limit != "tool" || exceeded.RetryAfterSeconds() != 2 {
+		t.Fatalf("second call: got %v, want tool limit with 2s retry", err)
+	}
+	if err := e.CheckTool(ctx, p, "search"); err != nil {
+		t.Fatalf("other tool: %v", err)
+	}
+}
+
+func TestEnforcer_DailyQuota(t *testing.T) {
+	e := NewEnforcer(Policy{DailyQuota: 1}, &fakeQuotas{calls: map[string]int{}})
+	now := time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC)
+	e.now = func() time.Time { return now }
+	ctx := context.Background()
+	p := auth.Principal{APIKey: "ci"}
+
+	if err := e.CheckTool(ctx, p, "search"); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	var exceeded *ExceededError
+	if err := e.CheckTool(ctx, p, "search"); !errors.As(err, &exceeded) || exceeded.Limit != "daily_quota" || exceeded.RetryAfter != time.Hour {
+		t.Fatalf("second call: got %v, want daily quota until midnight", err)
+	}
+}
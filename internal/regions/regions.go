@@ -0,0 +1,109 @@
+// Package regions provides the metadata of Google Cloud regions from an
+// embedded dataset and keeps the regions table in step with the regions
+// SKUs are offered in.
+package regions
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"mcp-server/internal/database"
+)
+
+//go:embed regions.json
+var dataset []byte
+
+type entry struct {
+	ID           string   `json:"id"`
+	DisplayName  string   `json:"display_name"`
+	Continent    string   `json:"continent"`
+	Country      string   `json:"country"`
+	MultiRegion  bool     `json:"multi_region"`
+	MultiRegions []string `json:"multi_regions"`
+	Zones        []string `json:"zones"`
+}
+
+// Known returns the regions of the embedded dataset.
+func Known() []database.Region {
+	var entries []entry
+	if err := json.Unmarshal(dataset, &entries); err != nil {
+		panic(fmt.Sprintf("regions: invalid embedded dataset: %v", err))
+	}
+	out := make([]database.Region, len(entries))
+	for i, e := range entries {
+		out[i] = database.Region{
+			RegionID:     e.ID,
+			DisplayName:  e.DisplayName,
+			Continent:    e.Continent,
+			Country:      e.Country,
+			MultiRegion:  e.MultiRegion,
+			MultiRegions: e.MultiRegions,
+			Zones:        e.Zones,
+		}
+	}
+	return out
+}
+
+// continents maps region ID prefixes to continents.
+var continents = []struct{ prefix, continent string }{
+	{"africa-", "Africa"},
+	{"asia-", "Asia"},
+	{"australia-", "Oceania"},
+	{"europe-", "Europe"},
+	{"me-", "Middle East"},
+	{"northamerica-", "North America"},
+	{"southamerica-", "South America"},
+	{"us-", "North America"},
+}
+
+// Guess describes a region missing from the dataset, such as one launched
+// after it was last updated, using the continent implied by its ID.
+func Guess(id string) database.Region {
+	g := database.Region{RegionID: id, DisplayName: id, Continent: "Unknown"}
+	for _, c := range continents {
+		if strings.HasPrefix(id, c.prefix) {
+			g.Continent = c.continent
+			break
+		}
+	}
+	return g
+}
+
+// Store reads SKU regions and writes the regions table.
+type Store interface {
+	UpsertRegion(ctx context.Context, g database.Region) error
+	SKURegions(ctx context.Context) ([]string, error)
+}
+
+var _ Store = (*database.SQLRepository)(nil)
+
+// Refresh writes the embedded dataset to the regions table and adds a guessed
+// entry for every region SKUs are offered in that the dataset lacks. It
+// returns the number of guessed regions.
+func Refresh(ctx context.Context, s Store) (int, error) {
+	known := map[string]bool{}
+	for _, g := range Known() {
+		if err := s.UpsertRegion(ctx, g); err != nil {
+			return 0, fmt.Errorf("write region %s: %w", g.RegionID, err)
+		}
+		known[g.RegionID] = true
+	}
+	ids, err := s.SKURegions(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list SKU regions: %w", err)
+	}
+	guessed := 0
+	for _, id := range ids {
+		if known[id] {
+			continue
+		}
+		if err := s.UpsertRegion(ctx, Guess(id)); err != nil {
+			return 0, fmt.Errorf("write region %s: %w", id, err)
+		}
+		guessed++
+	}
+	return guessed, nil
+}
@@ -0,0 +1,71 @@
+package regions
+
+import (
+	"context"
+	"testing"
+
+	"mcp-server/internal/database"
+)
+
+func TestKnown(t *testing.T) {
+	seen := map[string]bool{}
+	for _, g := range Known() {
+		if g.RegionID == "" || g.DisplayName == "" || g.Continent == "" {
+			t.Fatalf("incomplete region %+v", g)
+		}
+		if seen[g.RegionID] {
+			t.Fatalf("duplicate region %s", g.RegionID)
+		}
+		seen[g.RegionID] = true
+	}
+	for _, g := range Known() {
+		for _, m := range g.MultiRegions {
+			if !seen[m] {
+				t.Errorf("%s belongs to unknown multi-region %s", g.RegionID, m)
+			}
+		}
+	}
+	if !seen["us-central1"] || !seen["us"] {
+		t.Fatal("dataset lacks us-central1 or us")
+	}
+}
+
+func TestGuess(t *testing.T) {
+	if g := Guess("europe-west99"); g.Continent != "Europe" || g.DisplayName != "europe-west99" {
+		t.Fatalf("Guess = %+v", g)
+	}
+	if g := Guess("nam4"); g.Continent != "Unknown" {
+		t.Fatalf("Guess(nam4) = %+v", g)
+	}
+}
+
+type fakeStore struct {
+	skuRegions []string
+	written    map[string]database.Region
+}
+
+func (f *fakeStore) UpsertRegion(ctx context.Context, g database.Region) error {
+	f.written[g.RegionID] = g
+	return nil
+}
+
+func (f *fakeStore) SKURegions(ctx context.Context) ([]string, error) {
+	return f.skuRegions, nil
+}
+
+func TestRefresh(t *testing.T) {
+	s := &fakeStore{skuRegions: []string{"us-central1", "asia-future1"}, written: map[string]database.Region{}}
+	guessed, err := Refresh(context.Background(), s)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if guessed != 1 || len(s.written) != len(Known())+1 {
+		t.Fatalf("guessed %d, wrote %d regions", guessed, len(s.written))
+	}
+	if g := s.written["asia-future1"]; g.Continent != "Asia" {
+		t.Fatalf("guessed region = %+v", g)
+	}
+	if g := s.written["us-central1"]; g.DisplayName != "Council Bluffs, Iowa" {
+		t.Fatalf("dataset region overwritten by a guess: %+v", g)
+	}
+}
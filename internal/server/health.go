@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"mcp-server/internal/database"
+)
+
+// syncSuccess is the pricing_updates status of a completed sync run.
+const syncSuccess = "SUCCESS"
+
+// statusTimeout bounds the database queries of /readyz and /status.
+const statusTimeout = 5 * time.Second
+
+// StatusStore reports database health and catalog freshness.
+type StatusStore interface {
+	Ping(ctx context.Context) error
+	PendingMigrations(ctx context.Context) ([]string, error)
+	LatestPricingUpdate(ctx context.Context, status string) (database.PricingUpdate, error)
+	CatalogStats(ctx context.Context) (database.CatalogStats, error)
+}
+
+var _ StatusStore = (*database.SQLRepository)(nil)
+
+// healthz reports that the process is up.
+func (s *Server) healthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// notReady is why the server is not ready. The health routes are public, so
+// they show only the failed check; the error behind it is logged.
+type notReady struct {
+	check string
+	err   error
+}
+
+func (e *notReady) Error() string {
+	return e.check + ": " + e.err.Error()
+}
+
+func (e *notReady) Unwrap() error {
+	return e.err
+}
+
+// readyz reports whether the server can answer pricing queries: the
+// database is reachable, fully migrated and synced recently enough.
+func (s *Server) readyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), statusTimeout)
+	defer cancel()
+	if err := s.ready(ctx); err != nil {
+		slog.WarnContext(ctx, "not ready", "error", err)
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "unavailable", "error": err.check})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) ready(ctx context.Context) *notReady {
+	if s.draining.Load() {
+		return &notReady{"shutting down", errors.New("draining")}
+	}
+	if err := s.status.Ping(ctx); err != nil {
+		return &notReady{"database unreachable", err}
+	}
+	pending, err := s.status.PendingMigrations(ctx)
+	if err != nil {
+		return &notReady{"migrations unknown", err}
+	}
+	if len(pending) > 0 {
+		return &notReady{"migrations not applied", fmt.Errorf("pending %s", strings.Join(pending, ", "))}
+	}
+	if s.maxSyncAge <= 0 {
+		return nil
+	}
+	u, err := s.status.LatestPricingUpdate(ctx, syncSuccess)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &notReady{"no successful sync", err}
+	}
+	if err != nil {
+		return &notReady{"last sync unknown", err}
+	}
+	if age := s.now().Sub(u.UpdateTime); age > s.maxSyncAge {
+		return &notReady{"last successful sync too old", fmt.Errorf("%s ago exceeds %s", age.Round(time.Second), s.maxSyncAge)}
+	}
+	return nil
+}
+
+// syncStatus describes a pricing_updates row.
+type syncStatus struct {
+	UpdateTime      time.Time `json:"update_time"`
+	Status          string    `json:"status"`
+	ServicesUpdated int       `json:"services_updated"`
+	SkusUpdated     int       `json:"skus_updated"`
+	LogMessage      string    `json:"log_message,omitempty"`
+}
+
+type statusResponse struct {
+	Version        string      `json:"version"`
+	Ready          bool        `json:"ready"`
+	Error          string      `json:"error,omitempty"`
+	LastSync       *syncStatus `json:"last_sync,omitempty"`
+	NewestPrice    *time.Time  `json:"newest_price,omitempty"`
+	DataAgeSeconds *int64      `json:"data_age_seconds,omitempty"`
+	Services       int         `json:"services"`
+	SKUs           int         `json:"skus"`
+	PricingRows    int         `json:"pricing_rows"`
+}
+
+// statusz reports the last sync run, catalog size and freshness and the build
+// version. Like /readyz it names failed checks without their errors.
+func (s *Server) statusz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), statusTimeout)
+	defer cancel()
+	resp := statusResponse{Version: s.version}
+	if err := s.ready(ctx); err != nil {
+		slog.WarnContext(ctx, "not ready", "error", err)
+		resp.Error = err.check
+	} else {
+		resp.Ready = true
+	}
+	u, err := s.status.LatestPricingUpdate(ctx, "")
+	switch {
+	case err == nil:
+		resp.LastSync = &syncStatus{
+			UpdateTime:      u.UpdateTime.UTC(),
+			Status:          u.Status,
+			ServicesUpdated: u.ServicesUpdated,
+			SkusUpdated:     u.SkusUpdated,
+			LogMessage:      u.LogMessage,
+		}
+	case !errors.Is(err, sql.ErrNoRows):
+		slog.ErrorContext(ctx, "status: last sync", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "last sync unknown"})
+		return
+	}
+	stats, err := s.status.CatalogStats(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "status: catalog stats", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "catalog stats unknown"})
+		return
+	}
+	resp.Services, resp.SKUs, resp.PricingRows = stats.Services, stats.SKUs, stats.PricingRows
+	if !stats.NewestPrice.IsZero() {
+		newest := stats.NewestPrice.UTC()
+		age := int64(s.now().Sub(newest).Seconds())
+		resp.NewestPrice, resp.DataAgeSeconds = &newest, &age
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"mcp-server/internal/database"
+)
+
+type fakeStatus struct {
+	pingErr error
+	pending []string
+	updates []database.PricingUpdate // newest first
+	stats   database.CatalogStats
+}
+
+func (f *fakeStatus) Ping(ctx context.Context) error { return f.pingErr }
+
+func (f *fakeStatus) PendingMigrations(ctx context.Context) ([]string, error) { return f.pending, nil }
+
+func (f *fakeStatus) LatestPricingUpdate(ctx context.Context, status string) (database.PricingUpdate, error) {
+	for _, u := range f.updates {
+		if status == "" || u.Status == status {
+			return u, nil
+		}
+	}
+	return database.PricingUpdate{}, sql.ErrNoRows
+}
+
+func (f *fakeStatus) CatalogStats(ctx context.Context) (database.CatalogStats, error) {
+	return f.stats, nil
+}
+
+func get(t *testing.T, s *Server, path string) (int, map[string]any) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("%s: decode %q: %v", path, rec.Body.String(), err)
+	}
+	return rec.Code, body
+}
+
+func TestServer_Healthz(t *testing.T) {
+	s := New(Options{})
+	if code, _ := get(t, s, "/healthz"); code != http.StatusOK {
+		t.Fatalf("healthz status %d", code)
+	}
+}
+
+func TestServer_Readyz(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	fresh := []database.PricingUpdate{
+		{UpdateTime: now.Add(-time.Hour), Status: "FAILED"},
+		{UpdateTime: now.Add(-2 * time.Hour), Status: "SUCCESS"},
+	}
+	stale := []database.PricingUpdate{{UpdateTime: now.Add(-48 * time.Hour), Status: "SUCCESS"}}
+	cases := []struct {
+		name   string
+		status *fakeStatus
+		want   int
+	}{
+		{"ready", &fakeStatus{updates: fresh}, http.StatusOK},
+		{"unreachable", &fakeStatus{pingErr: errors.New("boom"), updates: fresh}, http.StatusServiceUnavailable},
+		{"pending migrations", &fakeStatus{pending: []string{"005_x.sql"}, updates: fresh}, http.StatusServiceUnavailable},
+		{"stale sync", &fakeStatus{updates: stale}, http.StatusServiceUnavailable},
+		{"never synced", &fakeStatus{}, http.StatusServiceUnavailable},
+	}
+	for _, c := range cases {
+		s := New(Options{Status: c.status, MaxSyncAge: 24 * time.Hour})
+		s.now = func() time.Time { return now }
+		code, body := get(t, s, "/readyz")
+		if code != c.want {
+			t.Errorf("%s: status %d, want %d (%v)", c.name, code, c.want, body)
+		}
+		if msg, _ := body["error"].(string); strings.Contains(msg, "boom") || strings.Contains(msg, "005_x") {
+			t.Errorf("%s: error %q shows check details", c.name, msg)
+		}
+	}
+}
+
+func TestServer_Status(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	st := &fakeStatus{
+		updates: []database.PricingUpdate{{UpdateTime: now.Add(-time.Hour), Status: "SUCCESS", ServicesUpdated: 3, SkusUpdated: 40}},
+		stats:   database.CatalogStats{Services: 3, SKUs: 40, PricingRows: 55, NewestPrice: now.Add(-time.Minute)},
+	}
+	s := New(Options{Status: st, Version: "v1.2.3", MaxSyncAge: 24 * time.Hour})
+	s.now = func() time.Time { return now }
+	code, body := get(t, s, "/status")
+	if code != http.StatusOK {
+		t.Fatalf("status %d: %v", code, body)
+	}
+	if body["version"] != "v1.2.3" || body["ready"] != true || body["pricing_rows"] != 55.0 || body["data_age_seconds"] != 60.0 {
+		t.Fatalf("body = %v", body)
+	}
+	if last, _ := body["last_sync"].(map[string]any); last["skus_updated"] != 40.0 {
+		t.Fatalf("last_sync = %v", body["last_sync"])
+	}
+
+	st.pingErr = errors.New("dial tcp 10.0.0.7:443: connection refused")
+	if _, body := get(t, s, "/status"); body["ready"] != false || body["error"] != "database unreachable" {
+		t.Fatalf("unreachable body = %v", body)
+	}
+}
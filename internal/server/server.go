@@ -1 +1,119 @@
+// Package server wires the MCP endpoint and operational routes into an HTTP handler.
 package server
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	sdkauth "github.com/modelcontextprotocol/go-sdk/auth"
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"mcp-server/internal/auth"
+	"mcp-server/internal/logging"
+	"mcp-server/internal/mcp"
+	"mcp-server/internal/ratelimit"
+)
+
+// Options configures a Server.
+type Options struct {
+	// Authenticator authenticates every route registered with Handle.
+	Authenticator *auth.Authenticator
+	// Limits applies per-principal rate limits; nil disables them.
+	Limits *ratelimit.Enforcer
+	// MCP is served at /mcp to authenticated users.
+	MCP *sdk.Server
+	// Status backs /readyz and /status; nil disables both routes. It only
+	// reads, so it should be a repository on a database.ConnectReadOnly handle.
+	Status StatusStore
+	// Version is reported by /status.
+	Version string
+	// Metrics serves /metrics; nil disables the route.
+	Metrics http.Handler
+	// MaxSyncAge fails readiness when the last successful sync is older;
+	// zero disables the check.
+	MaxSyncAge time.Duration
+}
+
+// Server routes HTTP requests. Every route registered with Handle requires an
+// authenticated principal holding the route's role and is subject to the
+// per-principal rate limit; the health and metrics routes are public.
+type Server struct {
+	mux        *http.ServeMux
+	handler    http.Handler
+	authn      *auth.Authenticator
+	limits     *ratelimit.Enforcer
+	status     StatusStore
+	version    string
+	maxSyncAge time.Duration
+	now        func() time.Time
+	draining   atomic.Bool
+}
+
+// untraced lists the paths of operational routes excluded from tracing.
+var untraced = map[string]bool{"/healthz": true, "/readyz": true, "/metrics": true}
+
+// New creates a Server.
+func New(opts Options) *Server {
+	s := &Server{
+		mux:        http.NewServeMux(),
+		authn:      opts.Authenticator,
+		limits:     opts.Limits,
+		status:     opts.Status,
+		version:    opts.Version,
+		maxSyncAge: opts.MaxSyncAge,
+		now:        time.Now,
+	}
+	s.mux.HandleFunc("GET /healthz", s.healthz)
+	if s.status != nil {
+		s.mux.HandleFunc("GET /readyz", s.readyz)
+		s.mux.HandleFunc("GET /status", s.statusz)
+	}
+	if opts.Metrics != nil {
+		s.mux.Handle("GET /metrics", opts.Metrics)
+	}
+	// Requests are traced, continuing the trace context of their headers;
+	// probes and scrapes are not. Every request is assigned a request ID.
+	s.handler = otelhttp.NewHandler(logging.Middleware(s.mux), "http.server",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string { return r.Method + " " + r.URL.Path }),
+		otelhttp.WithFilter(func(r *http.Request) bool { return !untraced[r.URL.Path] }))
+	if opts.MCP != nil {
+		streamable := sdk.NewStreamableHTTPHandler(func(*http.Request) *sdk.Server { return opts.MCP }, nil)
+		s.Handle("/mcp", auth.RoleUser, s.rejectNewSessions(sdkauth.RequireBearerToken(mcp.VerifyPrincipal, nil)(streamable)))
+	}
+	return s
+}
+
+// Handle registers h for pattern, restricted to principals holding role.
+func (s *Server) Handle(pattern string, role auth.Role, h http.Handler) {
+	if s.limits != nil {
+		h = s.limits.Middleware(h)
+	}
+	s.mux.Handle(pattern, s.authn.Middleware(auth.RequireRole(role, h)))
+}
+
+// Drain prepares the server for shutdown: readiness fails so the load
+// balancer stops routing to it, and requests that would open a new MCP
+// session are rejected while existing sessions keep working.
+func (s *Server) Drain() {
+	s.draining.Store(true)
+}
+
+// rejectNewSessions answers requests without an MCP session ID with 503
+// once the server is draining, so clients retry on another instance.
+func (s *Server) rejectNewSessions(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.draining.Load() && r.Header.Get("Mcp-Session-Id") == "" {
+			w.Header().Set("Retry-After", "1")
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "shutting_down"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ServeHTTP dispatches the request to the matching route.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
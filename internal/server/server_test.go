@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-server/internal/auth"
+	"mcp-server/internal/mcp"
+)
+
+// fakeGitHub treats every token as the login of its owner.
+type fakeGitHub struct{}
+
+func (fakeGitHub) User(ctx context.Context, token string) (auth.GitHubUser, error) {
+	return auth.GitHubUser{Login: token}, nil
+}
+
+func (fakeGitHub) OrgMember(ctx context.Context, token, org string) (bool, error) {
+	return false, nil
+}
+
+func (fakeGitHub) TeamMember(ctx context.Context, token, org, team, login string) (bool, error) {
+	return false, nil
+}
+
+type bearerTransport struct{ token string }
+
+func (t bearerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+t.token)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	authn := auth.NewAuthenticator(fakeGitHub{}, auth.Options{
+		Roles: auth.RoleBindings{Users: map[string]auth.Role{"alice": auth.RoleAdmin}},
+	})
+	mcpServer := sdk.NewServer(&sdk.Implementation{Name: "test"}, nil)
+	mcpServer.AddReceivingMiddleware(mcp.ToolRoles{"trigger_sync": auth.RoleAdmin}.Middleware)
+	for _, name := range []string{"search", "trigger_sync"} {
+		sdk.AddTool(mcpServer, &sdk.Tool{Name: name}, func(ctx context.Context, req *sdk.CallToolRequest, in struct{}) (*sdk.CallToolResult, any, error) {
+			return &sdk.CallToolResult{Content: []sdk.Content{&sdk.TextContent{Text: "ok"}}}, nil, nil
+		})
+	}
+	s := New(Options{Authenticator: authn, MCP: mcpServer})
+	s.Handle("GET /admin/ping", auth.RoleAdmin, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func connect(t *testing.T, url, token string) *sdk.ClientSession {
+	t.Helper()
+	client := sdk.NewClient(&sdk.Implementation{Name: "test-client"}, nil)
+	cs, err := client.Connect(context.Background(), &sdk.StreamableClientTransport{
+		Endpoint:   url + "/mcp",
+		HTTPClient: &http.Client{Transport: bearerTransport{token}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("connect as %s: %v", token, err)
+	}
+	t.Cleanup(func() { cs.Close() })
+	return cs
+}
+
+func TestServer_ToolRoles(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+
+	bob := connect(t, srv.URL, "bob")
+	tools, err := bob.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("list tools: %v", err)
+	}
+	if len(tools.Tools) != 1 || tools.Tools[0].Name != "search" {
+		t.Fatalf("user sees tools %v, want only search", tools.Tools)
+	}
+	if _, err := bob.CallTool(ctx, &sdk.CallToolParams{Name: "search"}); err != nil {
+		t.Fatalf("user call search: %v", err)
+	}
+	_, err = bob.CallTool(ctx, &sdk.CallToolParams{Name: "trigger_sync"})
+	if err == nil || !strings.Contains(err.Error(), "requires the admin role") {
+		t.Fatalf("user call trigger_sync: got %v, want access denied", err)
+	}
+
+	alice := connect(t, srv.URL, "alice")
+	if _, err := alice.CallTool(ctx, &sdk.CallToolParams{Name: "trigger_sync"}); err != nil {
+		t.Fatalf("admin call trigger_sync: %v", err)
+	}
+}
+
+func TestServer_RouteRoles(t *testing.T) {
+	srv := newTestServer(t)
+	for token, want := range map[string]int{"alice": http.StatusOK, "bob": http.StatusForbidden, "": http.StatusUnauthorized} {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/admin/ping", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request as %q: %v", token, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("request as %q: status %d, want %d", token, resp.StatusCode, want)
+		}
+	}
+}
+
+func TestServer_Drain(t *testing.T) {
+	authn := auth.NewAuthenticator(fakeGitHub{}, auth.Options{})
+	mcpServer := sdk.NewServer(&sdk.Implementation{Name: "test"}, nil)
+	sdk.AddTool(mcpServer, &sdk.Tool{Name: "search"}, func(ctx context.Context, req *sdk.CallToolRequest, in struct{}) (*sdk.CallToolResult, any, error) {
+		return &sdk.CallToolResult{Content: []sdk.Content{&sdk.TextContent{Text: "ok"}}}, nil, nil
+	})
+	s := New(Options{Authenticator: authn, MCP: mcpServer, Status: &fakeStatus{}})
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+	ctx := context.Background()
+
+	existing := connect(t, srv.URL, "bob")
+	s.Drain()
+	if _, err := existing.CallTool(ctx, &sdk.CallToolParams{Name: "search"}); err != nil {
+		t.Fatalf("existing session call while draining: %v", err)
+	}
+	client := sdk.NewClient(&sdk.Implementation{Name: "test-client"}, nil)
+	if _, err := client.Connect(ctx, &sdk.StreamableClientTransport{
+		Endpoint:   srv.URL + "/mcp",
+		HTTPClient: &http.Client{Transport: bearerTransport{"carol"}},
+	}, nil); err == nil {
+		t.Fatal("new session accepted while draining")
+	}
+	if code, _ := get(t, s, "/readyz"); code != http.StatusServiceUnavailable {
+		t.Fatalf("readyz while draining: status %d, want 503", code)
+	}
+}
@@ -37,6 +37,8 @@ func cleanDB(t *testing.T) {
 		"DELETE FROM pricing_updates",
 		"DELETE FROM skus",
 		"DELETE FROM services",
+		"DELETE FROM regions",
+		"DELETE FROM machine_types",
 	}
 	for _, stmt := range stmts {
 		if _, err := testDB.ExecContext(ctx, stmt); err != nil {
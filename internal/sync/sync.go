@@ -2,65 +2,188 @@ package sync
 
 import (
 	"context"
+	"log/slog"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/status"
+
+	"mcp-server/internal/compute"
 	"mcp-server/internal/database"
+	"mcp-server/internal/logging"
+	"mcp-server/internal/regions"
+	"mcp-server/internal/tracing"
 )
 
+var tracer = otel.Tracer("mcp-server/internal/sync")
+
 // CatalogClient defines the interface for retrieving GCP catalog data.
 type CatalogClient interface {
 	ListServices(ctx context.Context) ([]database.Service, error)
 	ListSkus(ctx context.Context, serviceID string) ([]database.SKU, []database.PricingInfo, error)
 }
 
+// Metrics receives the progress and outcome of a sync run.
+type Metrics interface {
+	ServicesProcessed(n int)
+	SKUsProcessed(n int)
+	APIError(code string)
+	RunFinished(d time.Duration, err error)
+}
+
+// Repricer re-prices saved estimates against the pricing a run stored and
+// returns how many of them changed.
+type Repricer interface {
+	RepriceEstimates(ctx context.Context) (int, error)
+}
+
 // Job synchronizes pricing data from GCP into the local database.
 type Job struct {
-	client CatalogClient
-	repo   database.Repository
+	client      CatalogClient
+	repo        database.Repository
+	metrics     Metrics
+	repricer    Repricer
+	concurrency int
 }
 
-// NewJob creates a new Job.
+// NewJob creates a new Job that syncs one service at a time.
 func NewJob(client CatalogClient, repo database.Repository) *Job {
-	return &Job{client: client, repo: repo}
+	return &Job{client: client, repo: repo, metrics: nopMetrics{}, concurrency: 1}
+}
+
+// SetConcurrency sets how many services are synced in parallel.
+func (j *Job) SetConcurrency(n int) {
+	j.concurrency = max(n, 1)
+}
+
+// SetMetrics reports the progress and outcome of every run to m.
+func (j *Job) SetMetrics(m Metrics) {
+	j.metrics = m
+}
+
+// SetRepricer re-prices saved estimates with r after every successful run.
+func (j *Job) SetRepricer(r Repricer) {
+	j.repricer = r
 }
 
-// Run executes the synchronization process.
+// Run executes the synchronization process in one trace, so catalog calls
+// and database writes of a run share a root span. Every log line of the run
+// carries a fresh run ID.
 func (j *Job) Run(ctx context.Context) error {
+	ctx = logging.With(ctx, slog.String(logging.KeyRunID, logging.NewID()))
+	ctx, span := tracer.Start(ctx, "sync.Run")
+	start := time.Now()
+	slog.InfoContext(ctx, "sync started")
+	err := j.run(ctx)
+	d := time.Since(start)
+	j.metrics.RunFinished(d, err)
+	if err != nil {
+		slog.ErrorContext(ctx, "sync failed", "error", err, "duration", d.String())
+	}
+	tracing.End(span, err)
+	return err
+}
+
+func (j *Job) run(ctx context.Context) error {
 	services, err := j.client.ListServices(ctx)
 	if err != nil {
+		j.apiError(err)
 		return err
 	}
-	var servicesUpdated, skusUpdated int
+	slog.InfoContext(ctx, "listed services", "services", len(services))
+	var servicesUpdated, skusUpdated atomic.Int64
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(j.concurrency)
 	for _, svc := range services {
-		if err := j.repo.UpsertService(ctx, svc); err != nil {
-			return err
-		}
-		servicesUpdated++
-		skus, prices, err := j.client.ListSkus(ctx, svc.ServiceID)
-		if err != nil {
-			return err
-		}
-		for _, sku := range skus {
-			if err := j.repo.UpsertSKU(ctx, sku); err != nil {
-				return err
-			}
-			skusUpdated++
-		}
-		for _, p := range prices {
-			if err := j.repo.UpsertPricingInfo(ctx, p); err != nil {
+		g.Go(func() error {
+			skus, err := j.syncService(gctx, svc)
+			if err != nil {
 				return err
 			}
-		}
+			servicesUpdated.Add(1)
+			skusUpdated.Add(int64(skus))
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	guessed, err := regions.Refresh(ctx, j.repo)
+	if err != nil {
+		return err
+	}
+	if guessed > 0 {
+		slog.WarnContext(ctx, "SKUs offered in regions missing from the region dataset", "regions", guessed)
+	}
+	if err := compute.RefreshMachineTypes(ctx, j.repo); err != nil {
+		return err
 	}
 	update := database.PricingUpdate{
 		UpdateTime:      time.Now().UTC(),
 		Status:          "SUCCESS",
-		ServicesUpdated: servicesUpdated,
-		SkusUpdated:     skusUpdated,
+		ServicesUpdated: int(servicesUpdated.Load()),
+		SkusUpdated:     int(skusUpdated.Load()),
 		LogMessage:      "sync completed",
 	}
 	if err := j.repo.InsertPricingUpdate(ctx, update); err != nil {
 		return err
 	}
+	slog.InfoContext(ctx, "sync completed", "services", update.ServicesUpdated, "skus", update.SkusUpdated)
+	if j.repricer != nil {
+		// The catalog is already stored, so a failure here is logged but does
+		// not fail the run; the next run re-prices from the same totals.
+		changed, err := j.repricer.RepriceEstimates(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "reprice estimates", "error", err)
+		} else {
+			slog.InfoContext(ctx, "repriced estimates", "changed", changed)
+		}
+	}
 	return nil
 }
+
+// syncService writes svc with its SKUs and prices and returns the number of SKUs.
+func (j *Job) syncService(ctx context.Context, svc database.Service) (int, error) {
+	ctx = logging.With(ctx, slog.String(logging.KeyServiceID, svc.ServiceID))
+	if err := j.repo.UpsertService(ctx, svc); err != nil {
+		slog.ErrorContext(ctx, "write service", "error", err)
+		return 0, err
+	}
+	j.metrics.ServicesProcessed(1)
+	skus, prices, err := j.client.ListSkus(ctx, svc.ServiceID)
+	if err != nil {
+		j.apiError(err)
+		slog.ErrorContext(ctx, "list SKUs", "error", err)
+		return 0, err
+	}
+	for _, sku := range skus {
+		if err := j.repo.UpsertSKU(ctx, sku); err != nil {
+			slog.ErrorContext(ctx, "write SKU", "sku_id", sku.SKUID, "error", err)
+			return 0, err
+		}
+	}
+	j.metrics.SKUsProcessed(len(skus))
+	for _, p := range prices {
+		if err := j.repo.UpsertPricingInfo(ctx, p); err != nil {
+			slog.ErrorContext(ctx, "write pricing info", "sku_id", p.SKUID, "error", err)
+			return 0, err
+		}
+	}
+	slog.DebugContext(ctx, "synced service", "skus", len(skus), "prices", len(prices))
+	return len(skus), nil
+}
+
+// apiError counts a failed catalog call by its gRPC status code, such as
+// "Unavailable" or "ResourceExhausted".
+func (j *Job) apiError(err error) {
+	j.metrics.APIError(status.Code(err).String())
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) ServicesProcessed(int)            {}
+func (nopMetrics) SKUsProcessed(int)                {}
+func (nopMetrics) APIError(string)                  {}
+func (nopMetrics) RunFinished(time.Duration, error) {}
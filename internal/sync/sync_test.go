@@ -2,9 +2,16 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"mcp-server/internal/compute"
 	"mcp-server/internal/database"
+	"mcp-server/internal/regions"
 )
 
 type fakeClient struct{}
@@ -44,4 +51,116 @@ func TestJob_Run(t *testing.T) {
 	assertCount("skus", 1)
 	assertCount("pricing_updates", 1)
 	assertCount("pricing_info", 1)
+	assertCount("regions", len(regions.Known()))
+	_, machineTypes := compute.MachineTypes()
+	assertCount("machine_types", len(machineTypes))
+}
+
+type fakeMetrics struct {
+	services, skus int
+	apiErrors      []string
+	runs           int
+	lastErr        error
+}
+
+func (m *fakeMetrics) ServicesProcessed(n int) { m.services += n }
+func (m *fakeMetrics) SKUsProcessed(n int)     { m.skus += n }
+func (m *fakeMetrics) APIError(code string)    { m.apiErrors = append(m.apiErrors, code) }
+func (m *fakeMetrics) RunFinished(d time.Duration, err error) {
+	m.runs++
+	m.lastErr = err
+}
+
+type failingClient struct{ fakeClient }
+
+func (failingClient) ListSkus(ctx context.Context, serviceID string) ([]database.SKU, []database.PricingInfo, error) {
+	return nil, nil, grpcstatus.Error(codes.Unavailable, "catalog unavailable")
+}
+
+func TestJob_Metrics(t *testing.T) {
+	cleanDB(t)
+	m := &fakeMetrics{}
+	job := NewJob(fakeClient{}, testRepo)
+	job.SetMetrics(m)
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if m.services != 1 || m.skus != 1 || m.runs != 1 || m.lastErr != nil {
+		t.Fatalf("metrics = %+v", m)
+	}
+
+	m = &fakeMetrics{}
+	job = NewJob(failingClient{}, testRepo)
+	job.SetMetrics(m)
+	if err := job.Run(context.Background()); err == nil {
+		t.Fatal("expected run to fail")
+	}
+	if len(m.apiErrors) != 1 || m.apiErrors[0] != "Unavailable" || m.lastErr == nil {
+		t.Fatalf("metrics = %+v", m)
+	}
+}
+
+type manyServicesClient struct{ fakeClient }
+
+func (manyServicesClient) ListServices(ctx context.Context) ([]database.Service, error) {
+	var services []database.Service
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		services = append(services, database.Service{ServiceID: id, DisplayName: id, BusinessEntityName: "Ent"})
+	}
+	return services, nil
+}
+
+func (manyServicesClient) ListSkus(ctx context.Context, serviceID string) ([]database.SKU, []database.PricingInfo, error) {
+	sku := database.SKU{SKUID: "sku-" + serviceID, ServiceID: serviceID, SkuName: "SKU", Description: "desc"}
+	price := database.PricingInfo{SKUID: sku.SKUID, CurrencyCode: "USD", UsageUnit: "h", TieredRates: []database.TieredRate{}}
+	return []database.SKU{sku}, []database.PricingInfo{price}, nil
+}
+
+func TestJob_Concurrency(t *testing.T) {
+	cleanDB(t)
+	job := NewJob(manyServicesClient{}, testRepo)
+	job.SetConcurrency(3)
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	var services, skus int
+	if err := testDB.QueryRow("SELECT services_updated, skus_updated FROM pricing_updates").Scan(&services, &skus); err != nil {
+		t.Fatalf("read update: %v", err)
+	}
+	if services != 5 || skus != 5 {
+		t.Fatalf("recorded %d services and %d SKUs, want 5 and 5", services, skus)
+	}
+}
+
+type fakeRepricer struct {
+	calls int
+	err   error
+}
+
+func (r *fakeRepricer) RepriceEstimates(ctx context.Context) (int, error) {
+	r.calls++
+	return 0, r.err
+}
+
+func TestJob_Repricer(t *testing.T) {
+	cleanDB(t)
+	r := &fakeRepricer{err: errors.New("estimates unavailable")}
+	job := NewJob(fakeClient{}, testRepo)
+	job.SetRepricer(r)
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("run failed with the repricer: %v", err)
+	}
+	if r.calls != 1 {
+		t.Fatalf("repricer called %d times, want 1", r.calls)
+	}
+
+	r = &fakeRepricer{}
+	job = NewJob(failingClient{}, testRepo)
+	job.SetRepricer(r)
+	if err := job.Run(context.Background()); err == nil {
+		t.Fatal("expected run to fail")
+	}
+	if r.calls != 0 {
+		t.Fatal("estimates repriced after a failed run")
+	}
 }
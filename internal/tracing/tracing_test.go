@@ -0,0 +1,45 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetup_ExportsToCollector(t *testing.T) {
+	var exports atomic.Int32
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/traces" {
+			exports.Add(1)
+		}
+	}))
+	defer collector.Close()
+
+	ctx := context.Background()
+	shutdown, err := Setup(ctx, Config{Endpoint: collector.URL, ServiceName: "test", ServiceVersion: "dev"})
+	if err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	_, span := otel.Tracer("test").Start(ctx, "work")
+	span.End()
+	if err := shutdown(ctx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if exports.Load() == 0 {
+		t.Fatal("collector received no spans")
+	}
+}
+
+func TestSetup_NoEndpoint(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Config{})
+	if err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+// Package units parses the usage units of catalog pricing, such as "h",
+// "GiBy.mo" or "count", and converts quantities between compatible units.
+//
+// Units are written as UCUM-style codes joined by ".", with an optional "/"
+// before the units of the denominator: "GiBy.mo" is gibibyte months and
+// "By/s" bytes per second. Common spellings such as "GB", "TiB", "hours" or
+// "GB-month" are accepted too.
+package units
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ErrIncompatible is wrapped by errors converting between units of different
+// dimensions, such as hours and gibibyte months.
+var ErrIncompatible = errors.New("incompatible units")
+
+// Dimension is what a unit measures.
+type Dimension string
+
+// Dimensions of units.
+const (
+	Data  Dimension = "data"
+	Time  Dimension = "time"
+	Count Dimension = "count"
+)
+
+// symbol is one unit a usage unit is the product of. Data is measured in
+// bytes and time in hours; months add months times the hours per month.
+type symbol struct {
+	code   string
+	dim    Dimension
+	scale  float64
+	months float64
+}
+
+var symbols = []symbol{
+	{code: "By", dim: Data, scale: 1},
+	{code: "kBy", dim: Data, scale: 1e3},
+	{code: "MBy", dim: Data, scale: 1e6},
+	{code: "GBy", dim: Data, scale: 1e9},
+	{code: "TBy", dim: Data, scale: 1e12},
+	{code: "PBy", dim: Data, scale: 1e15},
+	{code: "KiBy", dim: Data, scale: 1 << 10},
+	{code: "MiBy", dim: Data, scale: 1 << 20},
+	{code: "GiBy", dim: Data, scale: 1 << 30},
+	{code: "TiBy", dim: Data, scale: 1 << 40},
+	{code: "PiBy", dim: Data, scale: 1 << 50},
+	{code: "s", dim: Time, scale: 1.0 / 3600},
+	{code: "min", dim: Time, scale: 1.0 / 60},
+	{code: "h", dim: Time, scale: 1},
+	{code: "d", dim: Time, scale: 24},
+	{code: "mo", dim: Time, months: 1},
+	{code: "a", dim: Time, months: 12},
+	{code: "count", dim: Count, scale: 1},
+}
+
+// aliases maps lower-case spellings other than the codes to codes.
+var aliases = map[string]string{
+	"b": "By", "byte": "By", "bytes": "By",
+	"kb": "kBy", "mb": "MBy", "gb": "GBy", "tb": "TBy", "pb": "PBy",
+	"kib": "KiBy", "mib": "MiBy", "gib": "GiBy", "tib": "TiBy", "pib": "PiBy",
+	"sec": "s", "second": "s", "seconds": "s",
+	"minute": "min", "minutes": "min",
+	"hr": "h", "hrs": "h", "hour": "h", "hours": "h",
+	"day": "d", "days": "d",
+	"month": "mo", "months": "mo",
+	"yr": "a", "year": "a", "years": "a",
+	"request": "count", "requests": "count",
+}
+
+func lookup(name string) (symbol, bool) {
+	if code, ok := aliases[strings.ToLower(name)]; ok {
+		name = code
+	}
+	for _, s := range symbols {
+		if s.code == name {
+			return s, true
+		}
+	}
+	// Annotations such as {request} count things.
+	if len(name) > 2 && name[0] == '{' && name[len(name)-1] == '}' {
+		return symbol{code: name, dim: Count, scale: 1}, true
+	}
+	return symbol{}, false
+}
+
+type term struct {
+	symbol
+	exp int // 1 in the numerator, -1 in the denominator
+}
+
+// Unit is a parsed usage unit.
+type Unit struct {
+	expr  string
+	terms []term
+}
+
+// Parse parses expr, such as "GiBy.mo". "1" is a unit without dimension.
+func Parse(expr string) (Unit, error) {
+	s := strings.TrimSpace(expr)
+	if s == "" {
+		return Unit{}, errors.New("empty unit")
+	}
+	s = strings.NewReplacer("·", ".", "*", ".", "-", ".", " ", ".").Replace(s)
+	num, den, hasDen := strings.Cut(s, "/")
+	u := Unit{expr: strings.TrimSpace(expr)}
+	for _, part := range []struct {
+		text string
+		exp  int
+	}{{num, 1}, {den, -1}} {
+		if part.exp < 0 && !hasDen {
+			break
+		}
+		for _, name := range strings.Split(part.text, ".") {
+			if name == "1" {
+				continue
+			}
+			sym, ok := lookup(name)
+			if !ok {
+				return Unit{}, fmt.Errorf("unknown unit %q in %q; use codes such as h, mo, GiBy, GBy, TiBy or count", name, expr)
+			}
+			u.terms = append(u.terms, term{symbol: sym, exp: part.exp})
+		}
+	}
+	return u, nil
+}
+
+// String returns the expression u was parsed from.
+func (u Unit) String() string {
+	return u.expr
+}
+
+// Dimension describes what u measures, such as "data × time" for "GiBy.mo"
+// or "dimensionless" for "1".
+func (u Unit) Dimension() string {
+	var num, den []string
+	for _, d := range []Dimension{Data, Time, Count} {
+		for e := u.exponent(d); e != 0; {
+			if e > 0 {
+				num = append(num, string(d))
+				e--
+			} else {
+				den = append(den, string(d))
+				e++
+			}
+		}
+	}
+	s := strings.Join(num, " × ")
+	switch {
+	case s == "" && len(den) == 0:
+		return "dimensionless"
+	case s == "":
+		s = "1"
+	}
+	if len(den) > 0 {
+		s += " / " + strings.Join(den, " × ")
+	}
+	return s
+}
+
+func (u Unit) exponent(d Dimension) int {
+	e := 0
+	for _, t := range u.terms {
+		if t.dim == d {
+			e += t.exp
+		}
+	}
+	return e
+}
+
+// Compatible reports whether quantities in u can be converted to v.
+func (u Unit) Compatible(v Unit) bool {
+	for _, d := range []Dimension{Data, Time, Count} {
+		if u.exponent(d) != v.exponent(d) {
+			return false
+		}
+	}
+	return true
+}
+
+// Converter converts quantities between units.
+type Converter struct {
+	// HoursPerMonth is the length of a month in hours, which must be set to
+	// convert units involving months or years.
+	HoursPerMonth float64
+}
+
+// Factor returns the number of to units in one from unit.
+func (c Converter) Factor(from, to Unit) (float64, error) {
+	if !from.Compatible(to) {
+		return 0, fmt.Errorf("%w: cannot convert %s (%s) to %s (%s)", ErrIncompatible, from, from.Dimension(), to, to.Dimension())
+	}
+	hours := c.HoursPerMonth
+	if (from.monthly() || to.monthly()) && (hours <= 0 || math.IsNaN(hours) || math.IsInf(hours, 0)) {
+		return 0, fmt.Errorf("hours per month %v must be a positive number", hours)
+	}
+	return c.scale(from, hours) / c.scale(to, hours), nil
+}
+
+// Convert converts quantity in from units to to units.
+func (c Converter) Convert(quantity float64, from, to Unit) (float64, error) {
+	f, err := c.Factor(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return quantity * f, nil
+}
+
+// monthly reports whether u has a term measured in months, whose size in
+// hours depends on the length of a month.
+func (u Unit) monthly() bool {
+	for _, t := range u.terms {
+		if t.months != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// scale returns the size of u in bytes, hours and counts.
+func (Converter) scale(u Unit, hoursPerMonth float64) float64 {
+	scale := 1.0
+	for _, t := range u.terms {
+		scale *= math.Pow(t.scale+t.months*hoursPerMonth, float64(t.exp))
+	}
+	return scale
+}
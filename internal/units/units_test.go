@@ -0,0 +1,111 @@
+package units
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr, dimension string
+	}{
+		{"h", "time"},
+		{"mo", "time"},
+		{"By", "data"},
+		{"count", "count"},
+		{"GiBy.mo", "data × time"},
+		{"GiBy.h", "data × time"},
+		{"GB-month", "data × time"},
+		{"TiB", "data"},
+		{"By/s", "data / time"},
+		{"1/mo", "1 / time"},
+		{"{request}", "count"},
+		{"1", "dimensionless"},
+	}
+	for _, tt := range tests {
+		u, err := Parse(tt.expr)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := u.Dimension(); got != tt.dimension {
+			t.Errorf("Parse(%q).Dimension() = %q, want %q", tt.expr, got, tt.dimension)
+		}
+	}
+	for _, expr := range []string{"", "furlong", "GiBy.fortnight", "/s"} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) succeeded", expr)
+		}
+	}
+}
+
+func TestConverter_Convert(t *testing.T) {
+	tests := []struct {
+		quantity      float64
+		from, to      string
+		hoursPerMonth float64
+		want          float64
+	}{
+		{730, "h", "mo", 730, 1},
+		{1, "mo", "h", 720, 720},
+		{2, "d", "h", 730, 48},
+		{1, "GiBy", "By", 730, 1 << 30},
+		{1, "TB", "GiBy", 730, 1e12 / (1 << 30)},
+		{1024, "GiB.h", "GiBy.mo", 730, 1024.0 / 730},
+		{1, "TiB-month", "GiBy.mo", 730, 1024},
+		{10, "GBy.h", "GiBy.h", 730, 10e9 / (1 << 30)},
+		{3600, "By/s", "By/h", 730, 3600 * 3600},
+		{1e6, "requests", "count", 730, 1e6},
+		{12, "mo", "a", 730, 1},
+	}
+	for _, tt := range tests {
+		from, err := Parse(tt.from)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.from, err)
+		}
+		to, err := Parse(tt.to)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.to, err)
+		}
+		got, err := Converter{HoursPerMonth: tt.hoursPerMonth}.Convert(tt.quantity, from, to)
+		if err != nil {
+			t.Errorf("Convert(%v %s to %s): %v", tt.quantity, tt.from, tt.to, err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-9*math.Max(1, math.Abs(tt.want)) {
+			t.Errorf("Convert(%v %s to %s) = %v, want %v", tt.quantity, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestConverter_Incompatible(t *testing.T) {
+	for _, pair := range [][2]string{{"h", "GiBy.mo"}, {"GiBy", "GiBy.mo"}, {"count", "h"}, {"By/s", "By.s"}} {
+		from, _ := Parse(pair[0])
+		to, _ := Parse(pair[1])
+		if from.Compatible(to) {
+			t.Errorf("%s is compatible with %s", pair[0], pair[1])
+		}
+		if _, err := (Converter{HoursPerMonth: 730}).Convert(1, from, to); !errors.Is(err, ErrIncompatible) {
+			t.Errorf("Convert(%s to %s): got %v, want ErrIncompatible", pair[0], pair[1], err)
+		}
+	}
+	h, _ := Parse("h")
+	mo, _ := Parse("mo")
+	for _, hours := range []float64{0, -1} {
+		if _, err := (Converter{HoursPerMonth: hours}).Convert(1, mo, h); err == nil {
+			t.Errorf("hours per month %v accepted", hours)
+		}
+	}
+}
+
+func TestConverter_WithoutMonths(t *testing.T) {
+	// Only months and years need the length of a month.
+	for _, pair := range [][2]string{{"GiBy", "By"}, {"d", "h"}, {"GiBy.h", "MiBy.min"}} {
+		from, _ := Parse(pair[0])
+		to, _ := Parse(pair[1])
+		if _, err := (Converter{}).Convert(1, from, to); err != nil {
+			t.Errorf("Convert(%s to %s) without hours per month: %v", pair[0], pair[1], err)
+		}
+	}
+}